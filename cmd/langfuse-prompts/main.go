@@ -0,0 +1,143 @@
+// Command langfuse-prompts keeps Langfuse prompts in sync with files in git.
+//
+// Usage:
+//
+//	langfuse-prompts pull [-dir prompts] [-format yaml|json] [-name NAME] [-label production]
+//	langfuse-prompts diff [-dir prompts] [-label production]
+//	langfuse-prompts push [-dir prompts] [-label production] [-labels staging,...] -m "commit message"
+//
+// pull writes the prompts with the given label to one file per prompt, diff
+// compares the local files against the labeled versions and exits with status
+// 1 on drift, and push creates a new version with the label for every local
+// file that differs.
+// The client is configured with the usual LANGFUSE_* environment variables.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/optible/langfuse-go"
+	"github.com/optible/langfuse-go/promptfile"
+)
+
+const (
+	exitOK    = 0
+	exitDrift = 1
+	exitError = 2
+
+	defaultDir   = "prompts"
+	defaultLabel = "production"
+)
+
+// errDrift is returned by diff when local and remote prompts differ
+var errDrift = errors.New("prompts differ from Langfuse")
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitError
+	}
+
+	s := &syncer{client: langfuse.New(ctx), out: stdout}
+
+	var err error
+	switch args[0] {
+	case "pull":
+		err = runPull(ctx, s, args[1:])
+	case "diff":
+		err = runDiff(ctx, s, args[1:])
+	case "push":
+		err = runPush(ctx, s, args[1:])
+	case "help", "-h", "--help":
+		usage(stdout)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+		usage(stderr)
+		return exitError
+	}
+
+	switch {
+	case errors.Is(err, errDrift):
+		fmt.Fprintln(stderr, err)
+		return exitDrift
+	case err != nil:
+		fmt.Fprintln(stderr, "error:", err)
+		return exitError
+	default:
+		return exitOK
+	}
+}
+
+func runPull(ctx context.Context, s *syncer, args []string) error {
+	fs := flag.NewFlagSet("pull", flag.ContinueOnError)
+	dir := fs.String("dir", defaultDir, "directory to write prompt files to")
+	format := fs.String("format", string(promptfile.FormatYAML), "file format: yaml or json")
+	name := fs.String("name", "", "only pull the prompt with this name")
+	label := fs.String("label", defaultLabel, "label of the versions to pull")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f := promptfile.Format(*format)
+	if f != promptfile.FormatYAML && f != promptfile.FormatJSON {
+		return fmt.Errorf("unsupported format %q", *format)
+	}
+
+	return s.pull(ctx, *dir, f, *name, *label)
+}
+
+func runDiff(ctx context.Context, s *syncer, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	dir := fs.String("dir", defaultDir, "directory containing the prompt files")
+	label := fs.String("label", defaultLabel, "label of the versions to compare against")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return s.diff(ctx, *dir, *label)
+}
+
+func runPush(ctx context.Context, s *syncer, args []string) error {
+	fs := flag.NewFlagSet("push", flag.ContinueOnError)
+	dir := fs.String("dir", defaultDir, "directory containing the prompt files")
+	label := fs.String("label", defaultLabel, "label of the versions to compare against and to set on the new versions")
+	labels := fs.String("labels", "", "comma-separated additional labels to set on the new versions")
+	message := fs.String("m", "", "commit message for the new versions")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// The new versions get the compared label, so that they are in sync
+	// afterwards
+	newLabels := []string{*label}
+	for _, l := range strings.Split(*labels, ",") {
+		if l = strings.TrimSpace(l); l != "" && l != *label {
+			newLabels = append(newLabels, l)
+		}
+	}
+
+	return s.push(ctx, *dir, *label, newLabels, *message)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: langfuse-prompts <command> [flags]
+
+Commands:
+  pull   write prompts from Langfuse to local files
+  diff   compare local files against Langfuse, exit 1 on drift
+  push   create new versions for changed local files
+
+Run "langfuse-prompts <command> -h" for the flags of a command.
+`)
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakePromptServer serves the prompt endpoints used by the CLI from memory
+type fakePromptServer struct {
+	mu       sync.Mutex
+	versions map[string][]map[string]any
+}
+
+func (f *fakePromptServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/public/v2/prompts":
+		query := r.URL.Query()
+		data := []map[string]any{}
+		for name, versions := range f.versions {
+			if n := query.Get("name"); n != "" && n != name {
+				continue
+			}
+			if label := query.Get("label"); label != "" && findVersion(versions, label, 0) == nil {
+				continue
+			}
+			data = append(data, map[string]any{"name": name})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": data,
+			"meta": map[string]any{"page": 1, "totalPages": 1},
+		})
+
+	case r.Method == http.MethodGet:
+		name := strings.TrimPrefix(r.URL.Path, "/api/public/v2/prompts/")
+		version, _ := strconv.Atoi(r.URL.Query().Get("version"))
+		label := r.URL.Query().Get("label")
+		if label == "" && version == 0 {
+			label = "production"
+		}
+
+		prompt := findVersion(f.versions[name], label, version)
+		if prompt == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(prompt)
+
+	case r.Method == http.MethodPost:
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		name, _ := body["name"].(string)
+		body["version"] = float64(len(f.versions[name]) + 1)
+
+		// Labels are unique per prompt and "latest" moves to the new version
+		labels := []string{"latest"}
+		if requested, ok := body["labels"].([]any); ok {
+			for _, label := range requested {
+				labels = append(labels, label.(string))
+			}
+		}
+		for _, other := range f.versions[name] {
+			other["labels"] = slices.DeleteFunc(slices.Clone(labelsOf(other)), func(label string) bool {
+				return slices.Contains(labels, label)
+			})
+		}
+		body["labels"] = labels
+
+		f.versions[name] = append(f.versions[name], body)
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+// findVersion returns the version with the label, or with the number if
+// label is empty
+func findVersion(versions []map[string]any, label string, version int) map[string]any {
+	for _, v := range versions {
+		if label != "" && slices.Contains(labelsOf(v), label) {
+			return v
+		}
+		if label == "" && v["version"] == float64(version) {
+			return v
+		}
+	}
+	return nil
+}
+
+func labelsOf(v map[string]any) []string {
+	switch labels := v["labels"].(type) {
+	case []string:
+		return labels
+	case []any:
+		out := make([]string, len(labels))
+		for i, label := range labels {
+			out[i] = label.(string)
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func TestPullDiffPush(t *testing.T) {
+	server := &fakePromptServer{versions: map[string][]map[string]any{
+		"support/answer": {{
+			"type":    "chat",
+			"name":    "support/answer",
+			"version": float64(1),
+			"labels":  []string{"production", "latest"},
+			"prompt":  []map[string]any{{"role": "system", "content": "Be brief."}},
+		}},
+	}}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	t.Setenv("LANGFUSE_HOST", httpServer.URL)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	var stdout, stderr bytes.Buffer
+
+	if code := run(ctx, []string{"pull", "-dir", dir}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("pull exited with %d: %s", code, stderr.String())
+	}
+
+	path := filepath.Join(dir, "support", "answer.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected pulled file: %v", err)
+	}
+
+	if code := run(ctx, []string{"diff", "-dir", dir}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected no drift after pull, got %d: %s", code, stdout.String())
+	}
+
+	changed := strings.Replace(string(data), "Be brief.", "Be very brief.", 1)
+	if err := os.WriteFile(path, []byte(changed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout.Reset()
+	if code := run(ctx, []string{"diff", "-dir", dir}, &stdout, &stderr); code != exitDrift {
+		t.Fatalf("expected drift exit code, got %d", code)
+	}
+	if !strings.Contains(stdout.String(), `+       "content": "Be very brief."`) {
+		t.Errorf("expected diff output, got:\n%s", stdout.String())
+	}
+
+	if code := run(ctx, []string{"push", "-dir", dir, "-labels", "staging", "-m", "shorter"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("push exited with %d: %s", code, stderr.String())
+	}
+
+	versions := server.versions["support/answer"]
+	if len(versions) != 2 {
+		t.Fatalf("expected a new version, got %d versions", len(versions))
+	}
+	if versions[1]["commitMessage"] != "shorter" {
+		t.Errorf("expected commit message, got %v", versions[1]["commitMessage"])
+	}
+	if labels := labelsOf(versions[1]); !slices.Contains(labels, "production") || !slices.Contains(labels, "staging") {
+		t.Errorf("expected pushed version to be labeled production and staging, got %v", labels)
+	}
+	if labels := labelsOf(versions[0]); len(labels) != 0 {
+		t.Errorf("expected labels to move off the old version, got %v", labels)
+	}
+
+	if code := run(ctx, []string{"diff", "-dir", dir}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected no drift after push, got %d", code)
+	}
+
+	// Pushing again does not create another identical version
+	if code := run(ctx, []string{"push", "-dir", dir, "-m", "again"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("push exited with %d: %s", code, stderr.String())
+	}
+	if len(server.versions["support/answer"]) != 2 {
+		t.Errorf("expected no new version, got %d versions", len(server.versions["support/answer"]))
+	}
+}
+
+func TestPull_RejectsPathOutsideDir(t *testing.T) {
+	server := &fakePromptServer{versions: map[string][]map[string]any{
+		"../../etc/x": {{
+			"type":    "text",
+			"name":    "../../etc/x",
+			"version": float64(1),
+			"labels":  []string{"production"},
+			"prompt":  "Hi",
+		}},
+	}}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	t.Setenv("LANGFUSE_HOST", httpServer.URL)
+
+	dir := filepath.Join(t.TempDir(), "a", "b")
+	var stdout, stderr bytes.Buffer
+
+	if code := run(context.Background(), []string{"pull", "-dir", dir}, &stdout, &stderr); code != exitError {
+		t.Fatalf("expected exit code %d, got %d", exitError, code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..", "..", "etc", "x.yaml")); !os.IsNotExist(err) {
+		t.Errorf("expected no file outside the directory, got %v", err)
+	}
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"sync"}, &stdout, &stderr); code != exitError {
+		t.Errorf("expected exit code %d, got %d", exitError, code)
+	}
+}
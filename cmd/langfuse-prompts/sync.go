@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/optible/langfuse-go"
+	"github.com/optible/langfuse-go/model"
+	"github.com/optible/langfuse-go/promptfile"
+)
+
+// syncer pulls, diffs and pushes prompts between Langfuse and a directory
+type syncer struct {
+	client *langfuse.Langfuse
+	out    io.Writer
+}
+
+// localPrompt is a prompt read from a file
+type localPrompt struct {
+	path   string
+	prompt *model.Prompt
+}
+
+func (s *syncer) pull(ctx context.Context, dir string, format promptfile.Format, name, label string) error {
+	names := []string{name}
+	if name == "" {
+		var err error
+		names, err = s.remoteNames(ctx, &langfuse.PromptFilter{Label: label})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, n := range names {
+		// Prompt names may contain slashes to group them in folders
+		path, err := promptPath(dir, n, format.Extension())
+		if err != nil {
+			return err
+		}
+
+		prompt, err := s.fetch(ctx, n, label)
+		if err != nil {
+			return err
+		}
+
+		data, err := promptfile.Marshal(prompt, format)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", n, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(s.out, "pulled %s version %d -> %s\n", n, prompt.GetVersion(), path)
+	}
+
+	return nil
+}
+
+func (s *syncer) diff(ctx context.Context, dir, label string) error {
+	locals, err := readLocalPrompts(dir)
+	if err != nil {
+		return err
+	}
+
+	drift := 0
+	for _, local := range locals {
+		changed, remote, err := s.compare(ctx, local.prompt, label)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+
+		drift++
+		name := local.prompt.GetName()
+		if remote == nil {
+			fmt.Fprintf(s.out, "+ %s (%s): not in Langfuse\n", name, local.path)
+			continue
+		}
+
+		fmt.Fprintf(s.out, "~ %s (%s): differs from %s version %d\n", name, local.path, label, remote.GetVersion())
+		remoteText, err := canonicalText(remote)
+		if err != nil {
+			return err
+		}
+		localText, err := canonicalText(local.prompt)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(s.out, lineDiff(remoteText, localText))
+	}
+
+	if drift > 0 {
+		return fmt.Errorf("%w: %d of %d prompts changed", errDrift, drift, len(locals))
+	}
+
+	fmt.Fprintf(s.out, "%d prompts in sync with %s\n", len(locals), label)
+	return nil
+}
+
+func (s *syncer) push(ctx context.Context, dir, label string, labels []string, message string) error {
+	locals, err := readLocalPrompts(dir)
+	if err != nil {
+		return err
+	}
+
+	pushed := 0
+	for _, local := range locals {
+		changed, _, err := s.compare(ctx, local.prompt, label)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+
+		created, err := s.client.CreatePrompt(ctx, newVersion(local.prompt, labels, message))
+		if err != nil {
+			return err
+		}
+
+		pushed++
+		fmt.Fprintf(s.out, "pushed %s version %d\n", created.GetName(), created.GetVersion())
+	}
+
+	fmt.Fprintf(s.out, "%d of %d prompts pushed\n", pushed, len(locals))
+	return nil
+}
+
+// compare reports whether the local prompt differs from the labeled remote
+// version, returning the remote version if it exists
+func (s *syncer) compare(ctx context.Context, local *model.Prompt, label string) (bool, *model.Prompt, error) {
+	names, err := s.remoteNames(ctx, &langfuse.PromptFilter{Name: local.GetName(), Label: label})
+	if err != nil {
+		return false, nil, err
+	}
+	if len(names) == 0 {
+		return true, nil, nil
+	}
+
+	remote, err := s.fetch(ctx, local.GetName(), label)
+	if err != nil {
+		return false, nil, err
+	}
+
+	localText, err := canonicalText(local)
+	if err != nil {
+		return false, nil, err
+	}
+	remoteText, err := canonicalText(remote)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return localText != remoteText, remote, nil
+}
+
+// fetch returns the labeled version of a prompt with its references to other
+// prompts as written, so that pushing a pulled prompt keeps the references
+func (s *syncer) fetch(ctx context.Context, name, label string) (*model.Prompt, error) {
+	return s.client.GetPrompt(ctx, name, &langfuse.GetPromptOptions{
+		Label:        &label,
+		ForceRefresh: true,
+		Resolution:   langfuse.ResolveNone,
+	})
+}
+
+// remoteNames returns the sorted names of the prompts matching the filter
+func (s *syncer) remoteNames(ctx context.Context, filter *langfuse.PromptFilter) ([]string, error) {
+	metas, err := s.client.IterPrompts(filter).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(metas))
+	for _, meta := range metas {
+		if filter.Name == "" || meta.Name == filter.Name {
+			names = append(names, meta.Name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// readLocalPrompts reads all prompt files below dir
+func readLocalPrompts(dir string) ([]localPrompt, error) {
+	entries, err := promptfile.ReadFS(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	locals := make([]localPrompt, len(entries))
+	for i, entry := range entries {
+		path, err := promptPath(dir, entry.Path, "")
+		if err != nil {
+			return nil, err
+		}
+		locals[i] = localPrompt{path: path, prompt: entry.Prompt}
+	}
+
+	return locals, nil
+}
+
+// promptPath returns the path of a prompt file below dir, rejecting names
+// that would point outside of it, such as "../x" or "/x"
+func promptPath(dir, name, ext string) (string, error) {
+	rel := filepath.FromSlash(name) + ext
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("prompt %q is not a valid file path below %s", name, dir)
+	}
+	return filepath.Join(dir, rel), nil
+}
+
+// newVersion returns the prompt to create for a changed local prompt. Labels
+// from the file are not carried over, so that pushing does not silently
+// promote the new version.
+func newVersion(p *model.Prompt, labels []string, message string) *model.Prompt {
+	var commitMessage *string
+	if message != "" {
+		commitMessage = &message
+	}
+
+	if p.IsText() {
+		text := *p.TextPrompt
+		text.Labels = labels
+		text.CommitMessage = commitMessage
+		return &model.Prompt{TextPrompt: &text}
+	}
+
+	chat := *p.ChatPrompt
+	chat.Labels = labels
+	chat.CommitMessage = commitMessage
+	return &model.Prompt{ChatPrompt: &chat}
+}
+
+// canonicalText returns the content of a prompt that makes up a version, in
+// a stable indented JSON form suitable for comparing and diffing
+func canonicalText(p *model.Prompt) (string, error) {
+	file, err := promptfile.FromPrompt(p)
+	if err != nil {
+		return "", err
+	}
+
+	content := struct {
+		Type   model.PromptType `json:"type"`
+		Config any              `json:"config"`
+		Prompt json.RawMessage  `json:"prompt"`
+	}{file.Type, file.Config, file.Prompt}
+
+	// Round-trip through a generic value to normalize numbers and key order
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return "", err
+	}
+	data, err = json.MarshalIndent(generic, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
+
+// lineDiff returns a minimal line-based diff from a to b
+func lineDiff(a, b string) string {
+	x := strings.SplitAfter(a, "\n")
+	y := strings.SplitAfter(b, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			i++
+			j++
+		case j < len(y) && (i == len(x) || lcs[i][j+1] >= lcs[i+1][j]):
+			sb.WriteString("  + " + y[j])
+			j++
+		default:
+			sb.WriteString("  - " + x[i])
+			i++
+		}
+	}
+
+	return sb.String()
+}
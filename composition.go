@@ -0,0 +1,124 @@
+package langfuse
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// PromptResolution controls how references to other prompts, written as
+// @@@langfusePrompt:name=NAME|label=LABEL@@@, are resolved when fetching a
+// prompt
+type PromptResolution int
+
+const (
+	// ResolveOnServer lets Langfuse resolve the references
+	ResolveOnServer PromptResolution = iota
+
+	// ResolveLocally fetches the referenced prompts through the prompt cache
+	// and resolves the references in the client. Referenced prompts are
+	// cached individually, so composed prompts keep working offline once
+	// their dependencies have been fetched.
+	ResolveLocally
+
+	// ResolveNone returns the prompt with its references as written
+	ResolveNone
+)
+
+// resolvePromptReferences replaces the references in p by the referenced
+// text prompts, which are resolved recursively, and records the
+// resolution graph
+func (l *Langfuse) resolvePromptReferences(ctx context.Context, p *model.Prompt, opts *GetPromptOptions) (*model.Prompt, error) {
+	refs, err := p.References()
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return p, nil
+	}
+
+	resolving := append(slices.Clone(opts.resolving), p.GetName())
+	rootKey := promptNodeKey(p)
+	graph := &model.ResolutionGraph{
+		Root:         promptNode(p),
+		Dependencies: map[string][]model.PromptNode{},
+	}
+	replacement := map[string]string{}
+
+	for _, ref := range refs {
+		if slices.Contains(resolving, ref.Name) {
+			return nil, fmt.Errorf("circular prompt reference: %s -> %s", strings.Join(resolving, " -> "), ref.Name)
+		}
+
+		depOpts := &GetPromptOptions{
+			Version:      ref.Version,
+			Label:        ref.Label,
+			CacheTTL:     opts.CacheTTL,
+			FetchTimeout: opts.FetchTimeout,
+			ForceRefresh: opts.ForceRefresh,
+			Resolution:   ResolveLocally,
+			resolving:    resolving,
+		}
+		dep, err := l.GetPrompt(ctx, ref.Name, depOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve prompt reference %s: %w", ref.Tag, err)
+		}
+		if !dep.IsText() {
+			return nil, fmt.Errorf("prompt %s referenced by %s is not a text prompt", ref.Name, p.GetName())
+		}
+
+		replacement[ref.Tag] = dep.TextPrompt.Prompt
+		graph.Dependencies[rootKey] = append(graph.Dependencies[rootKey], promptNode(dep))
+
+		if depGraph := dep.GetResolutionGraph(); depGraph != nil {
+			for key, nodes := range depGraph.Dependencies {
+				graph.Dependencies[key] = nodes
+			}
+		}
+	}
+
+	resolved := p.ReplaceReferences(replacement)
+	resolved.SetResolutionGraph(graph)
+
+	return resolved, nil
+}
+
+// recordPromptDependents records that the cached prompt under cacheKey
+// embeds the prompts in its resolution graph, so that it is invalidated
+// along with them
+func (l *Langfuse) recordPromptDependents(cacheKey string, p *model.Prompt) {
+	graph := p.GetResolutionGraph()
+	if graph == nil {
+		return
+	}
+
+	for _, nodes := range graph.Dependencies {
+		for _, node := range nodes {
+			l.promptDependents.add(node.Name, cacheKey)
+		}
+	}
+}
+
+// invalidatePromptDependents removes the cached composed prompts that embed
+// any version of the named prompt
+func (l *Langfuse) invalidatePromptDependents(ctx context.Context, name string) {
+	for _, cacheKey := range l.promptDependents.take(name) {
+		l.invalidatePromptKey(ctx, cacheKey)
+	}
+}
+
+func promptNode(p *model.Prompt) model.PromptNode {
+	return model.PromptNode{ID: p.GetID(), Name: p.GetName(), Version: p.GetVersion()}
+}
+
+// promptNodeKey returns the key of a prompt in the dependencies of a
+// resolution graph: its ID, or its name if it was not fetched from Langfuse
+func promptNodeKey(p *model.Prompt) string {
+	if id := p.GetID(); id != "" {
+		return id
+	}
+	return p.GetName()
+}
@@ -0,0 +1,164 @@
+package langfuse
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// composedPromptHandler serves a chat prompt that references "persona",
+// which in turn references "tone"
+func composedPromptHandler(t *testing.T, requests *atomic.Int32, offline *atomic.Bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if offline.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Query().Get("resolve") != "false" {
+			t.Errorf("expected resolve=false, got %s", r.URL.RawQuery)
+		}
+
+		switch strings.TrimPrefix(r.URL.Path, "/api/public/v2/prompts/") {
+		case "assistant":
+			writeJSON(t, w, map[string]any{
+				"id": "p-assistant", "type": "chat", "name": "assistant", "version": 3,
+				"prompt": []map[string]any{
+					{"role": "system", "content": "@@@langfusePrompt:name=persona|label=production@@@ Help with {{topic}}."},
+				},
+			})
+		case "persona":
+			writeJSON(t, w, map[string]any{
+				"id": "p-persona", "type": "text", "name": "persona", "version": 1,
+				"prompt": "You are a pirate. @@@langfusePrompt:name=tone|version=2@@@",
+			})
+		case "tone":
+			if r.URL.Query().Get("version") != "2" {
+				t.Errorf("expected version 2, got %s", r.URL.RawQuery)
+			}
+			writeJSON(t, w, map[string]any{
+				"id": "p-tone", "type": "text", "name": "tone", "version": 2,
+				"prompt": "Be concise.",
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestGetPrompt_ResolveLocally(t *testing.T) {
+	var requests atomic.Int32
+	var offline atomic.Bool
+	l := newTestClient(t, composedPromptHandler(t, &requests, &offline))
+
+	prompt, err := l.GetPrompt(context.Background(), "assistant", &GetPromptOptions{Resolution: ResolveLocally})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	content := prompt.ChatPrompt.Prompt[0].Content
+	if content != "You are a pirate. Be concise. Help with {{topic}}." {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	graph := prompt.GetResolutionGraph()
+	if graph == nil || graph.Root.Name != "assistant" || graph.Root.Version != 3 {
+		t.Fatalf("unexpected graph %+v", graph)
+	}
+	if deps := graph.Dependencies["p-assistant"]; len(deps) != 1 || deps[0].Name != "persona" {
+		t.Errorf("unexpected assistant dependencies %+v", deps)
+	}
+	if deps := graph.Dependencies["p-persona"]; len(deps) != 1 || deps[0].Name != "tone" || deps[0].Version != 2 {
+		t.Errorf("unexpected persona dependencies %+v", deps)
+	}
+
+	// Dependencies are cached individually
+	offline.Store(true)
+	persona, err := l.GetPrompt(context.Background(), "persona", &GetPromptOptions{Resolution: ResolveLocally})
+	if err != nil {
+		t.Fatalf("expected cached dependency, got %v", err)
+	}
+	if persona.TextPrompt.Prompt != "You are a pirate. Be concise." {
+		t.Errorf("unexpected persona %q", persona.TextPrompt.Prompt)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("expected 3 requests, got %d", requests.Load())
+	}
+}
+
+func TestGetPrompt_ResolveNone(t *testing.T) {
+	var requests atomic.Int32
+	var offline atomic.Bool
+	l := newTestClient(t, composedPromptHandler(t, &requests, &offline))
+
+	prompt, err := l.GetPrompt(context.Background(), "persona", &GetPromptOptions{Resolution: ResolveNone})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if prompt.TextPrompt.Prompt != "You are a pirate. @@@langfusePrompt:name=tone|version=2@@@" {
+		t.Errorf("expected references to be kept, got %q", prompt.TextPrompt.Prompt)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %d", requests.Load())
+	}
+}
+
+func TestGetPrompt_ResolveLocallyCircular(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/api/public/v2/prompts/")
+		other := map[string]string{"a": "b", "b": "a"}[name]
+		writeJSON(t, w, map[string]any{
+			"type": "text", "name": name, "version": 1,
+			"prompt": "@@@langfusePrompt:name=" + other + "|label=production@@@",
+		})
+	})
+
+	_, err := l.GetPrompt(context.Background(), "a", &GetPromptOptions{Resolution: ResolveLocally})
+	if err == nil || !strings.Contains(err.Error(), "circular prompt reference: a -> b -> a") {
+		t.Errorf("expected circular reference error, got %v", err)
+	}
+}
+
+func TestInvalidatePrompt_ComposedDependents(t *testing.T) {
+	for _, resolution := range []PromptResolution{ResolveLocally, ResolveOnServer} {
+		var snippet atomic.Value
+		snippet.Store("Be concise.")
+		l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			name := strings.TrimPrefix(r.URL.Path, "/api/public/v2/prompts/")
+			switch {
+			case name == "snippet":
+				writeJSON(t, w, map[string]any{"id": "p-snippet", "type": "text", "name": "snippet", "version": 1, "prompt": snippet.Load()})
+			case r.URL.Query().Get("resolve") == "false":
+				writeJSON(t, w, map[string]any{"id": "p-page", "type": "text", "name": "page", "version": 1, "prompt": "@@@langfusePrompt:name=snippet|label=production@@@ Hi."})
+			default:
+				writeJSON(t, w, map[string]any{
+					"id": "p-page", "type": "text", "name": "page", "version": 1, "prompt": snippet.Load().(string) + " Hi.",
+					"resolutionGraph": map[string]any{
+						"root":         map[string]any{"id": "p-page", "name": "page", "version": 1},
+						"dependencies": map[string]any{"p-page": []map[string]any{{"id": "p-snippet", "name": "snippet", "version": 1}}},
+					},
+				})
+			}
+		})
+		ctx := context.Background()
+		opts := &GetPromptOptions{Resolution: resolution}
+
+		if _, err := l.GetPrompt(ctx, "page", opts); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		snippet.Store("Be verbose.")
+		l.InvalidatePrompt(ctx, PromptRef{Name: "snippet"})
+
+		page, err := l.GetPrompt(ctx, "page", opts)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if page.TextPrompt.Prompt != "Be verbose. Hi." {
+			t.Errorf("resolution %d: expected the composed prompt to be refetched, got %q", resolution, page.TextPrompt.Prompt)
+		}
+	}
+}
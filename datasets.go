@@ -0,0 +1,230 @@
+package langfuse
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// DatasetFilter contains the pagination options for listing datasets
+type DatasetFilter struct {
+	// Page is the 1-based page to fetch. Defaults to the first page.
+	Page int
+	// Limit is the number of items per page. Defaults to the API's default.
+	Limit int
+}
+
+func (f *DatasetFilter) params(page int) url.Values {
+	params := url.Values{}
+	setIntParam(params, "page", page)
+	setIntParam(params, "limit", f.Limit)
+	return params
+}
+
+// DatasetItemFilter contains the filters for listing dataset items.
+// Empty fields are not sent to the API.
+type DatasetItemFilter struct {
+	DatasetName         string
+	SourceTraceID       string
+	SourceObservationID string
+
+	// Page is the 1-based page to fetch. Defaults to the first page.
+	Page int
+	// Limit is the number of items per page. Defaults to the API's default.
+	Limit int
+}
+
+func (f *DatasetItemFilter) params(page int) url.Values {
+	params := url.Values{}
+	setIntParam(params, "page", page)
+	setIntParam(params, "limit", f.Limit)
+	setParam(params, "datasetName", f.DatasetName)
+	setParam(params, "sourceTraceId", f.SourceTraceID)
+	setParam(params, "sourceObservationId", f.SourceObservationID)
+	return params
+}
+
+// CreateDataset creates a dataset. Creating a dataset with an existing name
+// updates its description and metadata.
+func (l *Langfuse) CreateDataset(ctx context.Context, d *model.Dataset) (*model.Dataset, error) {
+	if d == nil || d.Name == "" {
+		return nil, fmt.Errorf("dataset name is required")
+	}
+
+	dataset := &model.Dataset{}
+	if err := l.postJSON(ctx, "/api/public/v2/datasets", d, dataset); err != nil {
+		return nil, fmt.Errorf("failed to create dataset: %w", err)
+	}
+
+	return dataset, nil
+}
+
+// GetDataset fetches a dataset by its name.
+func (l *Langfuse) GetDataset(ctx context.Context, name string) (*model.Dataset, error) {
+	if name == "" {
+		return nil, fmt.Errorf("dataset name is required")
+	}
+
+	dataset := &model.Dataset{}
+	if err := l.getJSON(ctx, "/api/public/v2/datasets/"+url.PathEscape(name), dataset); err != nil {
+		return nil, fmt.Errorf("failed to get dataset: %w", err)
+	}
+
+	return dataset, nil
+}
+
+// ListDatasets fetches a single page of datasets.
+// Use IterDatasets to walk all pages.
+func (l *Langfuse) ListDatasets(ctx context.Context, filter *DatasetFilter) (*model.DatasetList, error) {
+	if filter == nil {
+		filter = &DatasetFilter{}
+	}
+
+	return l.listDatasets(ctx, filter, filter.Page)
+}
+
+// IterDatasets returns an iterator over all datasets, starting at filter.Page.
+func (l *Langfuse) IterDatasets(filter *DatasetFilter) *Iterator[model.Dataset] {
+	if filter == nil {
+		filter = &DatasetFilter{}
+	}
+
+	return newIterator(filter.Page, func(ctx context.Context, page int) ([]model.Dataset, model.PaginationMeta, error) {
+		list, err := l.listDatasets(ctx, filter, page)
+		if err != nil {
+			return nil, model.PaginationMeta{}, err
+		}
+		return list.Data, list.Meta, nil
+	})
+}
+
+func (l *Langfuse) listDatasets(ctx context.Context, filter *DatasetFilter, page int) (*model.DatasetList, error) {
+	list := &model.DatasetList{}
+	if err := l.getJSON(ctx, buildPath("/api/public/v2/datasets", filter.params(page)), list); err != nil {
+		return nil, fmt.Errorf("failed to list datasets: %w", err)
+	}
+
+	return list, nil
+}
+
+// CreateDatasetItem creates a dataset item. If the item ID is set and already
+// exists, the item is updated instead.
+func (l *Langfuse) CreateDatasetItem(ctx context.Context, item *model.DatasetItem) (*model.DatasetItem, error) {
+	if item == nil || item.DatasetName == "" {
+		return nil, fmt.Errorf("dataset name is required")
+	}
+
+	created := &model.DatasetItem{}
+	if err := l.postJSON(ctx, "/api/public/dataset-items", item, created); err != nil {
+		return nil, fmt.Errorf("failed to create dataset item: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetDatasetItem fetches a dataset item by its ID.
+func (l *Langfuse) GetDatasetItem(ctx context.Context, itemID string) (*model.DatasetItem, error) {
+	if itemID == "" {
+		return nil, fmt.Errorf("dataset item ID is required")
+	}
+
+	item := &model.DatasetItem{}
+	if err := l.getJSON(ctx, "/api/public/dataset-items/"+url.PathEscape(itemID), item); err != nil {
+		return nil, fmt.Errorf("failed to get dataset item: %w", err)
+	}
+
+	return item, nil
+}
+
+// ListDatasetItems fetches a single page of dataset items matching the filter.
+// Use IterDatasetItems to walk all pages.
+func (l *Langfuse) ListDatasetItems(ctx context.Context, filter *DatasetItemFilter) (*model.DatasetItemList, error) {
+	if filter == nil {
+		filter = &DatasetItemFilter{}
+	}
+
+	return l.listDatasetItems(ctx, filter, filter.Page)
+}
+
+// IterDatasetItems returns an iterator over all dataset items matching the
+// filter, starting at filter.Page.
+func (l *Langfuse) IterDatasetItems(filter *DatasetItemFilter) *Iterator[model.DatasetItem] {
+	if filter == nil {
+		filter = &DatasetItemFilter{}
+	}
+
+	return newIterator(filter.Page, func(ctx context.Context, page int) ([]model.DatasetItem, model.PaginationMeta, error) {
+		list, err := l.listDatasetItems(ctx, filter, page)
+		if err != nil {
+			return nil, model.PaginationMeta{}, err
+		}
+		return list.Data, list.Meta, nil
+	})
+}
+
+func (l *Langfuse) listDatasetItems(ctx context.Context, filter *DatasetItemFilter, page int) (*model.DatasetItemList, error) {
+	list := &model.DatasetItemList{}
+	if err := l.getJSON(ctx, buildPath("/api/public/dataset-items", filter.params(page)), list); err != nil {
+		return nil, fmt.Errorf("failed to list dataset items: %w", err)
+	}
+
+	return list, nil
+}
+
+// ArchiveDatasetItem marks a dataset item as archived so that it is no longer
+// used in experiments, while keeping it and its past runs.
+func (l *Langfuse) ArchiveDatasetItem(ctx context.Context, itemID string) (*model.DatasetItem, error) {
+	item, err := l.GetDatasetItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.CreateDatasetItem(ctx, &model.DatasetItem{
+		ID:                  item.ID,
+		DatasetName:         item.DatasetName,
+		Status:              model.DatasetItemStatusArchived,
+		Input:               item.Input,
+		ExpectedOutput:      item.ExpectedOutput,
+		Metadata:            item.Metadata,
+		SourceTraceID:       item.SourceTraceID,
+		SourceObservationID: item.SourceObservationID,
+	})
+}
+
+// DeleteDatasetItem permanently deletes a dataset item by its ID.
+func (l *Langfuse) DeleteDatasetItem(ctx context.Context, itemID string) error {
+	if itemID == "" {
+		return fmt.Errorf("dataset item ID is required")
+	}
+
+	if err := l.deleteResource(ctx, "/api/public/dataset-items/"+url.PathEscape(itemID)); err != nil {
+		return fmt.Errorf("failed to delete dataset item: %w", err)
+	}
+
+	return nil
+}
+
+// CreateDatasetRunItem links a trace or observation to a dataset item as part
+// of the named dataset run. The run is created if it does not exist yet.
+func (l *Langfuse) CreateDatasetRunItem(ctx context.Context, runItem *model.DatasetRunItem) (*model.DatasetRunItem, error) {
+	if runItem == nil || runItem.RunName == "" {
+		return nil, fmt.Errorf("run name is required")
+	}
+
+	if runItem.DatasetItemID == "" {
+		return nil, fmt.Errorf("dataset item ID is required")
+	}
+
+	if runItem.TraceID == "" && runItem.ObservationID == "" {
+		return nil, fmt.Errorf("either trace ID or observation ID is required")
+	}
+
+	created := &model.DatasetRunItem{}
+	if err := l.postJSON(ctx, "/api/public/dataset-run-items", runItem, created); err != nil {
+		return nil, fmt.Errorf("failed to create dataset run item: %w", err)
+	}
+
+	return created, nil
+}
@@ -0,0 +1,142 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+func TestCreateDataset(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/public/v2/datasets" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if body["name"] != "regression" || body["description"] != "prompt regressions" {
+			t.Errorf("unexpected body %v", body)
+		}
+		writeJSON(t, w, map[string]any{"id": "ds-1", "name": "regression"})
+	})
+
+	dataset, err := l.CreateDataset(context.Background(), &model.Dataset{
+		Name:        "regression",
+		Description: "prompt regressions",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if dataset.ID != "ds-1" {
+		t.Errorf("expected dataset ID 'ds-1', got %q", dataset.ID)
+	}
+}
+
+func TestCreateDataset_WithoutName(t *testing.T) {
+	l := New(context.Background())
+
+	_, err := l.CreateDataset(context.Background(), &model.Dataset{})
+	if err == nil || err.Error() != "dataset name is required" {
+		t.Errorf("expected 'dataset name is required', got %v", err)
+	}
+}
+
+func TestCreateDatasetItem_Upsert(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var item model.DatasetItem
+		if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if item.ID != "item-1" || item.DatasetName != "regression" || item.SourceTraceID != "trace-1" {
+			t.Errorf("unexpected item %+v", item)
+		}
+		item.DatasetID = "ds-1"
+		writeJSON(t, w, item)
+	})
+
+	item, err := l.CreateDatasetItem(context.Background(), &model.DatasetItem{
+		ID:             "item-1",
+		DatasetName:    "regression",
+		Input:          model.M{"question": "2+2"},
+		ExpectedOutput: "4",
+		SourceTraceID:  "trace-1",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if item.DatasetID != "ds-1" || item.ExpectedOutput != "4" {
+		t.Errorf("unexpected item %+v", item)
+	}
+}
+
+func TestArchiveDatasetItem(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(t, w, map[string]any{
+				"id":          "item-1",
+				"datasetName": "regression",
+				"status":      "ACTIVE",
+				"input":       "hello",
+			})
+		case http.MethodPost:
+			var item model.DatasetItem
+			if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if item.Status != model.DatasetItemStatusArchived || item.Input != "hello" {
+				t.Errorf("unexpected item %+v", item)
+			}
+			writeJSON(t, w, item)
+		}
+	})
+
+	item, err := l.ArchiveDatasetItem(context.Background(), "item-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if item.Status != model.DatasetItemStatusArchived {
+		t.Errorf("expected status ARCHIVED, got %q", item.Status)
+	}
+}
+
+func TestIterDatasetItems(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("datasetName") != "regression" {
+			t.Errorf("unexpected datasetName %q", r.URL.Query().Get("datasetName"))
+		}
+		writeJSON(t, w, map[string]any{
+			"data": []map[string]any{{"id": "item-1"}, {"id": "item-2"}},
+			"meta": map[string]any{"page": 1, "limit": 50, "totalItems": 2, "totalPages": 1},
+		})
+	})
+
+	items, err := l.IterDatasetItems(&DatasetItemFilter{DatasetName: "regression"}).All(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Errorf("expected 2 items, got %d", len(items))
+	}
+}
+
+func TestDeleteDatasetItem(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/public/dataset-items/item-1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		writeJSON(t, w, map[string]any{"message": "deleted"})
+	})
+
+	if err := l.DeleteDatasetItem(context.Background(), "item-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
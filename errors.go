@@ -0,0 +1,131 @@
+package langfuse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const requestIDHeader = "X-Request-Id"
+
+var (
+	// ErrNotFound is matched by API errors with status 404
+	ErrNotFound = errors.New("not found")
+
+	// ErrUnauthorized is matched by API errors with status 401 or 403,
+	// e.g. because of invalid or missing API keys
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrRateLimited is matched by API errors with status 429
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// APIError is returned when the Langfuse API responds with an error status.
+// Use errors.Is with ErrNotFound, ErrUnauthorized or ErrRateLimited to check
+// for common failures, or errors.As for the details.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+
+	// Message and Type are decoded from the error body, if it is JSON
+	Message string
+	Type    string
+
+	// Body is the raw error body
+	Body string
+
+	// RequestID identifies the request in the Langfuse logs, if returned
+	RequestID string
+}
+
+func newAPIError(method, path string, statusCode int, header http.Header, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Path:       path,
+		Body:       string(body),
+		RequestID:  header.Get(requestIDHeader),
+	}
+
+	var decoded struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(body, &decoded) == nil {
+		e.Message = decoded.Message
+		e.Type = decoded.Error
+	}
+
+	return e
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "HTTP %d %s %s", e.StatusCode, e.Method, e.Path)
+
+	switch {
+	case e.Message != "":
+		b.WriteString(": " + e.Message)
+	case e.Body != "":
+		b.WriteString(": " + strings.TrimSpace(e.Body))
+	}
+
+	if e.RequestID != "" {
+		b.WriteString(" (request ID " + e.RequestID + ")")
+	}
+	return b.String()
+}
+
+// Is reports whether the error matches one of the sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
+
+// IngestionEventError is the error of a single event rejected by the
+// ingestion API while the rest of the batch was accepted
+type IngestionEventError struct {
+	// EventID is the ID of the rejected ingestion event
+	EventID string
+	Err     *APIError
+}
+
+func (e *IngestionEventError) Error() string {
+	return fmt.Sprintf("event %s: %v", e.EventID, e.Err)
+}
+
+func (e *IngestionEventError) Unwrap() error {
+	return e.Err
+}
+
+// IngestionError lists the events of a batch rejected by the ingestion API
+type IngestionError struct {
+	Failures []*IngestionEventError
+}
+
+func (e *IngestionError) Error() string {
+	messages := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		messages[i] = failure.Error()
+	}
+	return fmt.Sprintf("failed to ingest %d events: %s", len(e.Failures), strings.Join(messages, "; "))
+}
+
+func (e *IngestionError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure
+	}
+	return errs
+}
@@ -0,0 +1,126 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/optible/langfuse-go/internal/pkg/api"
+	"github.com/optible/langfuse-go/model"
+)
+
+func TestAPIError_Sentinels(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusTooManyRequests, ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+
+			err := l.DeleteScore(context.Background(), "score-1")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+			for _, other := range []error{ErrNotFound, ErrUnauthorized, ErrRateLimited} {
+				if other != tt.want && errors.Is(err, other) {
+					t.Errorf("expected %v not to match %v", err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIError_Details(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Prompt not found","error":"LangfuseNotFoundError"}`))
+	})
+
+	_, err := l.GetPrompt(context.Background(), "missing", nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Method != http.MethodGet || apiErr.Path != "/api/public/v2/prompts/missing" {
+		t.Errorf("unexpected request details %+v", apiErr)
+	}
+	if apiErr.Message != "Prompt not found" || apiErr.Type != "LangfuseNotFoundError" || apiErr.RequestID != "req-1" {
+		t.Errorf("unexpected error details %+v", apiErr)
+	}
+
+	want := "failed to fetch prompt: HTTP 404 GET /api/public/v2/prompts/missing: Prompt not found (request ID req-1)"
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestIngest_APIError(t *testing.T) {
+	newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+	})
+
+	err := ingest(context.Background(), api.New(), []model.IngestionEvent{{ID: "event-1"}})
+
+	var apiErr *APIError
+	if !errors.Is(err, ErrUnauthorized) || !errors.As(err, &apiErr) || apiErr.Path != "/api/public/ingestion" {
+		t.Errorf("expected unauthorized ingestion error, got %v", err)
+	}
+}
+
+func TestIngest_EventErrors(t *testing.T) {
+	newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = w.Write([]byte(`{"successes":[{"id":"event-1","status":201}],"errors":[{"id":"event-2","status":429,"message":"Too many events"}]}`))
+	})
+
+	err := ingest(context.Background(), api.New(), []model.IngestionEvent{{ID: "event-1"}, {ID: "event-2"}})
+
+	var ingestionErr *IngestionError
+	if !errors.As(err, &ingestionErr) || len(ingestionErr.Failures) != 1 {
+		t.Fatalf("expected one failed event, got %v", err)
+	}
+	if failure := ingestionErr.Failures[0]; failure.EventID != "event-2" || failure.Err.Message != "Too many events" {
+		t.Errorf("unexpected failure %+v", failure)
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected rate limited error, got %v", err)
+	}
+}
+
+func TestWithIngestionErrorHandler(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+	})
+
+	errs := make(chan error, 1)
+	l.WithIngestionErrorHandler(func(err error) { errs <- err })
+
+	if _, err := l.Trace(&model.Trace{Name: "trace"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	l.Flush(context.Background())
+
+	select {
+	case err := <-errs:
+		var apiErr *APIError
+		if !errors.Is(err, ErrRateLimited) || !errors.As(err, &apiErr) {
+			t.Errorf("expected rate limited API error, got %v", err)
+		}
+	default:
+		t.Error("expected the handler to be called")
+	}
+}
@@ -0,0 +1,98 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/optible/langfuse-go/evaluator"
+	"github.com/optible/langfuse-go/model"
+)
+
+// Evaluate runs the evaluators on the sample and records the resulting scores
+// on the trace and, if observationID is not empty, on the observation. Scores
+// are sent through the ingestion queue like those created with Score.
+// A failing evaluator does not stop the others; all errors are returned joined.
+func (l *Langfuse) Evaluate(
+	ctx context.Context,
+	traceID string,
+	observationID string,
+	sample *evaluator.Sample,
+	evaluators ...evaluator.Evaluator,
+) ([]model.Score, error) {
+	if traceID == "" {
+		return nil, fmt.Errorf("trace ID is required")
+	}
+
+	target := *sample
+	target.TraceID = traceID
+	target.ObservationID = observationID
+
+	var scores []model.Score
+	var errs []error
+
+	for _, ev := range evaluators {
+		results, err := ev.Evaluate(ctx, &target)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("evaluator failed: %w", err))
+			continue
+		}
+
+		for i := range results {
+			score := &results[i]
+			score.TraceID = traceID
+			if score.ObservationID == "" {
+				score.ObservationID = observationID
+			}
+
+			if _, err := l.Score(score); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			scores = append(scores, *score)
+		}
+	}
+
+	return scores, errors.Join(errs...)
+}
+
+// EvaluateTrace runs the evaluators on the input and output of the trace,
+// compared to the expected output, and records the scores on the trace.
+func (l *Langfuse) EvaluateTrace(
+	ctx context.Context,
+	t *model.Trace,
+	expected any,
+	evaluators ...evaluator.Evaluator,
+) ([]model.Score, error) {
+	sample := &evaluator.Sample{
+		Input:    t.Input,
+		Output:   t.Output,
+		Expected: expected,
+		Metadata: evaluator.MetadataMap(t.Metadata),
+	}
+
+	return l.Evaluate(ctx, t.ID, "", sample, evaluators...)
+}
+
+// EvaluateGeneration runs the evaluators on the input and output of the
+// generation, compared to the expected output, and records the scores on the
+// generation. The latency is taken from the start and end time, if both are set.
+func (l *Langfuse) EvaluateGeneration(
+	ctx context.Context,
+	g *model.Generation,
+	expected any,
+	evaluators ...evaluator.Evaluator,
+) ([]model.Score, error) {
+	sample := &evaluator.Sample{
+		Input:    g.Input,
+		Output:   g.Output,
+		Expected: expected,
+		Metadata: evaluator.MetadataMap(g.Metadata),
+	}
+
+	if g.StartTime != nil && g.EndTime != nil {
+		sample.Latency = g.EndTime.Sub(*g.StartTime)
+	}
+
+	return l.Evaluate(ctx, g.TraceID, g.ID, sample, evaluators...)
+}
@@ -0,0 +1,72 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/optible/langfuse-go/evaluator"
+	"github.com/optible/langfuse-go/model"
+)
+
+func TestEvaluateGeneration(t *testing.T) {
+	l := New(context.Background())
+
+	start := time.Now()
+	end := start.Add(300 * time.Millisecond)
+	g := &model.Generation{
+		ID:        "gen-1",
+		TraceID:   "trace-1",
+		Output:    "Paris",
+		StartTime: &start,
+		EndTime:   &end,
+	}
+
+	scores, err := l.EvaluateGeneration(context.Background(), g, "Paris",
+		&evaluator.ExactMatch{},
+		&evaluator.Latency{Max: time.Second},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(scores) != 2 {
+		t.Fatalf("expected 2 scores, got %d", len(scores))
+	}
+	for _, score := range scores {
+		if score.TraceID != "trace-1" || score.ObservationID != "gen-1" || score.ID == "" {
+			t.Errorf("expected score linked to generation, got %+v", score)
+		}
+		if score.Value != 1 {
+			t.Errorf("expected score %q to pass, got %v", score.Name, score.Value)
+		}
+	}
+}
+
+func TestEvaluate_ContinuesAfterError(t *testing.T) {
+	l := New(context.Background())
+
+	failing := evaluator.Func(func(ctx context.Context, s *evaluator.Sample) ([]model.Score, error) {
+		return nil, errors.New("boom")
+	})
+
+	scores, err := l.EvaluateTrace(context.Background(), &model.Trace{ID: "trace-1", Output: "x"}, "x",
+		failing,
+		&evaluator.ExactMatch{},
+	)
+	if err == nil {
+		t.Error("expected evaluator error")
+	}
+	if len(scores) != 1 || scores[0].ObservationID != "" {
+		t.Errorf("expected 1 trace score, got %+v", scores)
+	}
+}
+
+func TestEvaluate_WithoutTraceID(t *testing.T) {
+	l := New(context.Background())
+
+	if _, err := l.Evaluate(context.Background(), "", "", &evaluator.Sample{}); err == nil {
+		t.Error("expected error without trace ID")
+	}
+}
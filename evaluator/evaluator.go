@@ -0,0 +1,110 @@
+// Package evaluator provides a pluggable interface for scoring LLM outputs
+// and a set of built-in heuristic evaluators that need no network access.
+package evaluator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// Sample is the data an evaluator scores
+type Sample struct {
+	Input    any
+	Output   any
+	Expected any
+	Metadata map[string]any
+
+	// Latency is the time it took to produce the output, if known.
+	Latency time.Duration
+
+	// TraceID and ObservationID identify what is being evaluated. They are
+	// set by the client before the evaluators run.
+	TraceID       string
+	ObservationID string
+}
+
+// Evaluator scores a sample. The returned scores only need a name, value and
+// optionally a comment; the client links them to the evaluated trace or
+// observation before sending them.
+type Evaluator interface {
+	Evaluate(ctx context.Context, s *Sample) ([]model.Score, error)
+}
+
+// Func adapts an ordinary function to the Evaluator interface
+type Func func(ctx context.Context, s *Sample) ([]model.Score, error)
+
+// Evaluate calls f(ctx, s)
+func (f Func) Evaluate(ctx context.Context, s *Sample) ([]model.Score, error) {
+	return f(ctx, s)
+}
+
+// MetadataMap converts metadata of a trace, observation or dataset item to a
+// map, returning nil if it is not a JSON object.
+func MetadataMap(metadata any) map[string]any {
+	switch m := metadata.(type) {
+	case map[string]any:
+		return m
+	case model.M:
+		return m
+	default:
+		return nil
+	}
+}
+
+// Stringify returns the text representation of an input or output value,
+// encoding structured values as JSON
+func Stringify(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	case fmt.Stringer:
+		return s.String()
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// boolScore returns a boolean score with value 1 if ok and 0 otherwise
+func boolScore(name string, ok bool, comment string) model.Score {
+	value := 0.0
+	if ok {
+		value = 1
+	}
+
+	return model.Score{
+		Name:     name,
+		Value:    value,
+		DataType: model.ScoreDataTypeBoolean,
+		Comment:  comment,
+	}
+}
+
+// numericScore returns a numeric score
+func numericScore(name string, value float64, comment string) model.Score {
+	return model.Score{
+		Name:     name,
+		Value:    value,
+		DataType: model.ScoreDataTypeNumeric,
+		Comment:  comment,
+	}
+}
+
+// nameOr returns name if set, otherwise the default name
+func nameOr(name, defaultName string) string {
+	if name != "" {
+		return name
+	}
+	return defaultName
+}
@@ -0,0 +1,172 @@
+package evaluator
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+func evaluate(t *testing.T, e Evaluator, s *Sample) model.Score {
+	t.Helper()
+
+	scores, err := e.Evaluate(context.Background(), s)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(scores) != 1 {
+		t.Fatalf("expected 1 score, got %d", len(scores))
+	}
+	return scores[0]
+}
+
+func TestExactMatch(t *testing.T) {
+	score := evaluate(t, &ExactMatch{}, &Sample{Output: "Paris", Expected: "Paris"})
+	if score.Name != "exact_match" || score.Value != 1 || score.DataType != model.ScoreDataTypeBoolean {
+		t.Errorf("unexpected score %+v", score)
+	}
+
+	score = evaluate(t, &ExactMatch{}, &Sample{Output: " paris", Expected: "Paris"})
+	if score.Value != 0 {
+		t.Errorf("expected mismatch, got %+v", score)
+	}
+
+	score = evaluate(t, &ExactMatch{Name: "match", IgnoreCase: true, TrimSpace: true}, &Sample{Output: " paris", Expected: "Paris"})
+	if score.Name != "match" || score.Value != 1 {
+		t.Errorf("expected case-insensitive match, got %+v", score)
+	}
+
+	score = evaluate(t, &ExactMatch{}, &Sample{Output: model.M{"a": 1, "b": 2}, Expected: map[string]any{"b": 2, "a": 1}})
+	if score.Value != 1 {
+		t.Errorf("expected structured values to match, got %+v", score)
+	}
+}
+
+func TestRegex(t *testing.T) {
+	e := &Regex{Pattern: regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)}
+
+	if score := evaluate(t, e, &Sample{Output: "2024-01-31"}); score.Value != 1 {
+		t.Errorf("expected match, got %+v", score)
+	}
+	if score := evaluate(t, e, &Sample{Output: "Jan 31"}); score.Value != 0 {
+		t.Errorf("expected no match, got %+v", score)
+	}
+
+	if _, err := (&Regex{}).Evaluate(context.Background(), &Sample{}); err == nil {
+		t.Error("expected error without pattern")
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	e := &JSONSchema{Schema: map[string]any{
+		"type":     "object",
+		"required": []string{"answer", "confidence"},
+		"properties": map[string]any{
+			"answer":     map[string]any{"type": "string", "minLength": 1},
+			"confidence": map[string]any{"type": "number", "minimum": 0, "maximum": 1},
+			"tags":       map[string]any{"type": "array", "items": map[string]any{"enum": []string{"a", "b"}}},
+		},
+		"additionalProperties": false,
+	}}
+
+	tests := []struct {
+		name   string
+		output any
+		valid  bool
+	}{
+		{"valid string", `{"answer": "yes", "confidence": 0.9}`, true},
+		{"valid map", map[string]any{"answer": "yes", "confidence": 1, "tags": []string{"a"}}, true},
+		{"invalid JSON", `{"answer": `, false},
+		{"missing required", `{"answer": "yes"}`, false},
+		{"wrong type", `{"answer": 1, "confidence": 0.5}`, false},
+		{"above maximum", `{"answer": "yes", "confidence": 2}`, false},
+		{"not in enum", `{"answer": "yes", "confidence": 0.5, "tags": ["c"]}`, false},
+		{"additional property", `{"answer": "yes", "confidence": 0.5, "extra": true}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := evaluate(t, e, &Sample{Output: tt.output})
+			if (score.Value == 1) != tt.valid {
+				t.Errorf("expected valid=%v, got %+v", tt.valid, score)
+			}
+		})
+	}
+
+	if score := evaluate(t, &JSONSchema{}, &Sample{Output: `[1, 2]`}); score.Value != 1 {
+		t.Errorf("expected valid JSON without schema, got %+v", score)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	score := evaluate(t, &Levenshtein{}, &Sample{Output: "kitten", Expected: "sitting"})
+	expected := 1 - 3.0/7.0
+	if math.Abs(score.Value-expected) > 1e-9 {
+		t.Errorf("expected %v, got %v", expected, score.Value)
+	}
+
+	score = evaluate(t, &Levenshtein{}, &Sample{Output: "", Expected: ""})
+	if score.Value != 1 {
+		t.Errorf("expected identical empty strings to score 1, got %v", score.Value)
+	}
+}
+
+func TestRougeL(t *testing.T) {
+	score := evaluate(t, &RougeL{}, &Sample{
+		Output:   "the cat sat on the mat",
+		Expected: "the cat is on the mat",
+	})
+	// LCS "the cat on the mat" has 5 tokens of 6 in both
+	expected := 5.0 / 6.0
+	if math.Abs(score.Value-expected) > 1e-9 {
+		t.Errorf("expected %v, got %v", expected, score.Value)
+	}
+
+	score = evaluate(t, &RougeL{}, &Sample{Output: "foo", Expected: "bar"})
+	if score.Value != 0 {
+		t.Errorf("expected 0 for disjoint texts, got %v", score.Value)
+	}
+}
+
+func TestLength(t *testing.T) {
+	e := &Length{Min: 2, Max: 5}
+
+	if score := evaluate(t, e, &Sample{Output: "héllo"}); score.Value != 1 {
+		t.Errorf("expected within bounds, got %+v", score)
+	}
+	if score := evaluate(t, e, &Sample{Output: "a"}); score.Value != 0 {
+		t.Errorf("expected too short, got %+v", score)
+	}
+	if score := evaluate(t, e, &Sample{Output: "too long"}); score.Value != 0 {
+		t.Errorf("expected too long, got %+v", score)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	e := &Latency{Max: time.Second}
+
+	if score := evaluate(t, e, &Sample{Latency: 500 * time.Millisecond}); score.Value != 1 {
+		t.Errorf("expected within threshold, got %+v", score)
+	}
+	if score := evaluate(t, e, &Sample{Latency: 2 * time.Second}); score.Value != 0 {
+		t.Errorf("expected above threshold, got %+v", score)
+	}
+
+	scores, err := e.Evaluate(context.Background(), &Sample{Output: "no timing"})
+	if err != nil || len(scores) != 0 {
+		t.Errorf("expected no score without latency, got %+v, %v", scores, err)
+	}
+}
+
+func TestFunc(t *testing.T) {
+	e := Func(func(ctx context.Context, s *Sample) ([]model.Score, error) {
+		return []model.Score{{Name: "custom", Value: 0.5}}, nil
+	})
+
+	if score := evaluate(t, e, &Sample{}); score.Name != "custom" {
+		t.Errorf("unexpected score %+v", score)
+	}
+}
@@ -0,0 +1,183 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// ExactMatch scores 1 if the output equals the expected output, 0 otherwise.
+// Structured values are compared by their JSON encoding.
+type ExactMatch struct {
+	// Name is the score name. Defaults to "exact_match".
+	Name       string
+	IgnoreCase bool
+	TrimSpace  bool
+}
+
+func (e *ExactMatch) Evaluate(_ context.Context, s *Sample) ([]model.Score, error) {
+	output, expected := Stringify(s.Output), Stringify(s.Expected)
+	if e.TrimSpace {
+		output, expected = strings.TrimSpace(output), strings.TrimSpace(expected)
+	}
+
+	match := output == expected
+	if e.IgnoreCase {
+		match = strings.EqualFold(output, expected)
+	}
+
+	return []model.Score{boolScore(nameOr(e.Name, "exact_match"), match, "")}, nil
+}
+
+// Regex scores 1 if the output matches the pattern, 0 otherwise.
+type Regex struct {
+	// Name is the score name. Defaults to "regex".
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+func (e *Regex) Evaluate(_ context.Context, s *Sample) ([]model.Score, error) {
+	if e.Pattern == nil {
+		return nil, fmt.Errorf("regex pattern is required")
+	}
+
+	match := e.Pattern.MatchString(Stringify(s.Output))
+	return []model.Score{boolScore(nameOr(e.Name, "regex"), match, "")}, nil
+}
+
+// Levenshtein scores the similarity of the output and the expected output as
+// 1 - distance / max(len(output), len(expected)), in runes.
+type Levenshtein struct {
+	// Name is the score name. Defaults to "levenshtein_similarity".
+	Name       string
+	IgnoreCase bool
+}
+
+func (e *Levenshtein) Evaluate(_ context.Context, s *Sample) ([]model.Score, error) {
+	output, expected := Stringify(s.Output), Stringify(s.Expected)
+	if e.IgnoreCase {
+		output, expected = strings.ToLower(output), strings.ToLower(expected)
+	}
+
+	a, b := []rune(output), []rune(expected)
+	distance := levenshteinDistance(a, b)
+
+	similarity := 1.0
+	if maxLen := max(len(a), len(b)); maxLen > 0 {
+		similarity = 1 - float64(distance)/float64(maxLen)
+	}
+
+	comment := fmt.Sprintf("edit distance %d", distance)
+	return []model.Score{numericScore(nameOr(e.Name, "levenshtein_similarity"), similarity, comment)}, nil
+}
+
+func levenshteinDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// RougeL scores the ROUGE-L F1 measure of the output against the expected
+// output, based on the longest common subsequence of whitespace-separated tokens.
+type RougeL struct {
+	// Name is the score name. Defaults to "rouge_l".
+	Name       string
+	IgnoreCase bool
+}
+
+func (e *RougeL) Evaluate(_ context.Context, s *Sample) ([]model.Score, error) {
+	output, expected := Stringify(s.Output), Stringify(s.Expected)
+	if e.IgnoreCase {
+		output, expected = strings.ToLower(output), strings.ToLower(expected)
+	}
+
+	candidate, reference := strings.Fields(output), strings.Fields(expected)
+
+	f1 := 0.0
+	if lcs := lcsLength(candidate, reference); lcs > 0 {
+		precision := float64(lcs) / float64(len(candidate))
+		recall := float64(lcs) / float64(len(reference))
+		f1 = 2 * precision * recall / (precision + recall)
+	} else if len(candidate) == 0 && len(reference) == 0 {
+		f1 = 1
+	}
+
+	return []model.Score{numericScore(nameOr(e.Name, "rouge_l"), f1, "")}, nil
+}
+
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				curr[j] = prev[j-1] + 1
+			} else {
+				curr[j] = max(prev[j], curr[j-1])
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// Length scores 1 if the length of the output in runes is within the bounds,
+// 0 otherwise.
+type Length struct {
+	// Name is the score name. Defaults to "length".
+	Name string
+	Min  int
+	// Max is the maximum length. Zero means unbounded.
+	Max int
+}
+
+func (e *Length) Evaluate(_ context.Context, s *Sample) ([]model.Score, error) {
+	length := utf8.RuneCountInString(Stringify(s.Output))
+	ok := length >= e.Min && (e.Max == 0 || length <= e.Max)
+
+	comment := fmt.Sprintf("length %d", length)
+	return []model.Score{boolScore(nameOr(e.Name, "length"), ok, comment)}, nil
+}
+
+// Latency scores 1 if the sample latency is at most Max, 0 otherwise.
+// Samples without a latency, e.g. traces, are not scored.
+type Latency struct {
+	// Name is the score name. Defaults to "latency".
+	Name string
+	Max  time.Duration
+}
+
+func (e *Latency) Evaluate(_ context.Context, s *Sample) ([]model.Score, error) {
+	if e.Max <= 0 {
+		return nil, fmt.Errorf("latency threshold is required")
+	}
+	if s.Latency <= 0 {
+		return nil, nil
+	}
+
+	comment := fmt.Sprintf("latency %s", s.Latency)
+	return []model.Score{boolScore(nameOr(e.Name, "latency"), s.Latency <= e.Max, comment)}, nil
+}
@@ -0,0 +1,217 @@
+package evaluator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// JSONSchema scores 1 if the output is valid JSON that satisfies the schema,
+// 0 otherwise. String outputs are parsed as JSON; other values are validated
+// as they are. Without a schema only JSON validity is checked.
+//
+// The supported keywords are type, enum, const, properties, required,
+// additionalProperties, items, minItems, maxItems, minLength, maxLength,
+// minimum and maximum.
+type JSONSchema struct {
+	// Name is the score name. Defaults to "json_schema".
+	Name   string
+	Schema map[string]any
+}
+
+func (e *JSONSchema) Evaluate(_ context.Context, s *Sample) ([]model.Score, error) {
+	name := nameOr(e.Name, "json_schema")
+
+	value, err := decodeJSONValue(s.Output)
+	if err != nil {
+		return []model.Score{boolScore(name, false, "invalid JSON: "+err.Error())}, nil
+	}
+
+	if e.Schema != nil {
+		// Round-trip the schema so that it uses the same representation as the value
+		decoded, err := decodeJSONValue(e.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON schema: %w", err)
+		}
+		schema, _ := decoded.(map[string]any)
+		if err := validateSchema(value, schema, "$"); err != nil {
+			return []model.Score{boolScore(name, false, err.Error())}, nil
+		}
+	}
+
+	return []model.Score{boolScore(name, true, "")}, nil
+}
+
+// decodeJSONValue normalizes v to the generic representation produced by encoding/json
+func decodeJSONValue(v any) (any, error) {
+	var raw []byte
+	switch s := v.(type) {
+	case string:
+		raw = []byte(s)
+	case []byte:
+		raw = s
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		raw = b
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func validateSchema(value any, schema map[string]any, path string) error {
+	if t, ok := schema["type"]; ok && !matchesType(value, t) {
+		return fmt.Errorf("%s: expected type %v", path, t)
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !containsJSON(enum, value) {
+		return fmt.Errorf("%s: value not in enum", path)
+	}
+
+	if c, ok := schema["const"]; ok && !equalJSON(c, value) {
+		return fmt.Errorf("%s: value does not match const", path)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+
+		for _, key := range toStrings(schema["required"]) {
+			if _, ok := v[key]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, key)
+			}
+		}
+
+		for key, propValue := range v {
+			propSchema, ok := properties[key].(map[string]any)
+			if !ok {
+				if additional, isBool := schema["additionalProperties"].(bool); isBool && !additional {
+					return fmt.Errorf("%s: unexpected property %q", path, key)
+				}
+				if additional, isMap := schema["additionalProperties"].(map[string]any); isMap {
+					propSchema = additional
+				} else {
+					continue
+				}
+			}
+			if err := validateSchema(propValue, propSchema, path+"."+key); err != nil {
+				return err
+			}
+		}
+
+	case []any:
+		if minItems, ok := toFloat(schema["minItems"]); ok && float64(len(v)) < minItems {
+			return fmt.Errorf("%s: expected at least %v items", path, minItems)
+		}
+		if maxItems, ok := toFloat(schema["maxItems"]); ok && float64(len(v)) > maxItems {
+			return fmt.Errorf("%s: expected at most %v items", path, maxItems)
+		}
+		if itemSchema, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := validateSchema(item, itemSchema, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if minLength, ok := toFloat(schema["minLength"]); ok && length < minLength {
+			return fmt.Errorf("%s: expected at least %v characters", path, minLength)
+		}
+		if maxLength, ok := toFloat(schema["maxLength"]); ok && length > maxLength {
+			return fmt.Errorf("%s: expected at most %v characters", path, maxLength)
+		}
+
+	case float64:
+		if minimum, ok := toFloat(schema["minimum"]); ok && v < minimum {
+			return fmt.Errorf("%s: expected minimum %v", path, minimum)
+		}
+		if maximum, ok := toFloat(schema["maximum"]); ok && v > maximum {
+			return fmt.Errorf("%s: expected maximum %v", path, maximum)
+		}
+	}
+
+	return nil
+}
+
+// matchesType reports whether value matches a JSON schema type, which is
+// either a single type name or a list of them
+func matchesType(value any, schemaType any) bool {
+	types := toStrings(schemaType)
+	if name, ok := schemaType.(string); ok {
+		types = []string{name}
+	}
+
+	for _, name := range types {
+		switch v := value.(type) {
+		case nil:
+			if name == "null" {
+				return true
+			}
+		case bool:
+			if name == "boolean" {
+				return true
+			}
+		case float64:
+			if name == "number" || (name == "integer" && v == math.Trunc(v)) {
+				return true
+			}
+		case string:
+			if name == "string" {
+				return true
+			}
+		case []any:
+			if name == "array" {
+				return true
+			}
+		case map[string]any:
+			if name == "object" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func toStrings(v any) []string {
+	list, _ := v.([]any)
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func toFloat(v any) (float64, bool) {
+	n, ok := v.(float64)
+	return n, ok
+}
+
+func containsJSON(list []any, value any) bool {
+	for _, item := range list {
+		if equalJSON(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalJSON(a, b any) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(aJSON) == string(bJSON)
+}
@@ -27,7 +27,7 @@ func main() {
 	// Check if it's a text or chat prompt
 	if prompt.IsText() {
 		// Compile the text prompt with variables
-		compiled := prompt.TextPrompt.Compile(map[string]string{
+		compiled := prompt.TextPrompt.Compile(map[string]any{
 			"movie": "The Matrix",
 			"style": "technical",
 		})
@@ -36,10 +36,10 @@ func main() {
 
 	if prompt.IsChat() {
 		// Compile the chat prompt with variables
-		messages := prompt.ChatPrompt.Compile(map[string]string{
+		messages := prompt.ChatPrompt.Compile(map[string]any{
 			"movie": "The Matrix",
 			"style": "technical",
-		})
+		}, nil)
 		for i, msg := range messages {
 			fmt.Printf("Message %d [%s]: %s\n", i+1, msg.Role, msg.Content)
 		}
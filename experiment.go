@@ -0,0 +1,242 @@
+package langfuse
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/optible/langfuse-go/evaluator"
+	"github.com/optible/langfuse-go/model"
+)
+
+const (
+	defaultExperimentConcurrency = 4
+)
+
+// ExperimentItem is handed to the experiment task for every dataset item.
+type ExperimentItem struct {
+	Item *model.DatasetItem
+
+	// TraceID is the ID of the trace created for the item, to attach the
+	// observations of the task to. The trace itself is not exposed, as it
+	// is being sent in the background.
+	TraceID string
+
+	// ObservationID optionally links the dataset run item, and the scores
+	// emitted for it, to an observation created by the task, such as the
+	// generation that produced the output.
+	ObservationID string
+}
+
+// ExperimentTask runs the pipeline under test for a single dataset item and
+// returns its output. It is called inside a trace created for the item.
+type ExperimentTask func(ctx context.Context, item *ExperimentItem) (any, error)
+
+// Experiment describes a run of a task over all active items of a dataset.
+type Experiment struct {
+	DatasetName    string
+	RunName        string
+	RunDescription string
+	Metadata       any
+
+	Task ExperimentTask
+
+	// Evaluators score the task output against the expected output of the
+	// dataset item. The sample latency is the duration of the task.
+	Evaluators []evaluator.Evaluator
+
+	// MaxConcurrency bounds the number of items processed concurrently.
+	// Defaults to 4.
+	MaxConcurrency int
+}
+
+// ExperimentItemResult is the outcome of the experiment for one dataset item.
+type ExperimentItemResult struct {
+	Item    model.DatasetItem
+	TraceID string
+	Output  any
+	Scores  []model.Score
+
+	// Err is set if the task, an evaluator or linking the run item failed.
+	Err error
+}
+
+// ScoreAggregate summarizes the values of all scores sharing a name.
+type ScoreAggregate struct {
+	Count int
+	Mean  float64
+	Min   float64
+	Max   float64
+}
+
+// ExperimentResult summarizes an experiment run.
+type ExperimentResult struct {
+	RunName string
+	Items   []ExperimentItemResult
+	Failed  int
+
+	// Scores aggregates the emitted scores by name.
+	Scores map[string]ScoreAggregate
+}
+
+// RunExperiment runs the experiment task over every active item of the
+// dataset with bounded concurrency. Each execution gets its own trace which
+// is linked to the dataset item as part of the named dataset run, and the
+// scores returned by the evaluators are sent through the ingestion queue.
+// Call Flush afterwards to make sure all traces and scores are sent.
+func (l *Langfuse) RunExperiment(ctx context.Context, exp *Experiment) (*ExperimentResult, error) {
+	if exp == nil || exp.Task == nil {
+		return nil, fmt.Errorf("experiment task is required")
+	}
+
+	if exp.DatasetName == "" {
+		return nil, fmt.Errorf("dataset name is required")
+	}
+
+	if exp.RunName == "" {
+		return nil, fmt.Errorf("run name is required")
+	}
+
+	items, err := l.IterDatasetItems(&DatasetItemFilter{DatasetName: exp.DatasetName}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	active := items[:0]
+	for _, item := range items {
+		if item.Status != model.DatasetItemStatusArchived {
+			active = append(active, item)
+		}
+	}
+
+	concurrency := exp.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultExperimentConcurrency
+	}
+
+	results := make([]ExperimentItemResult, len(active))
+	sem := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+
+	for i := range active {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = l.runExperimentItem(ctx, exp, &active[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	return summarizeExperiment(exp.RunName, results), nil
+}
+
+func (l *Langfuse) runExperimentItem(ctx context.Context, exp *Experiment, item *model.DatasetItem) ExperimentItemResult {
+	result := ExperimentItemResult{Item: *item}
+
+	trace, err := l.Trace(&model.Trace{
+		Name:  exp.RunName,
+		Input: item.Input,
+		Metadata: model.M{
+			"datasetName":   exp.DatasetName,
+			"datasetItemId": item.ID,
+			"runName":       exp.RunName,
+		},
+	})
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.TraceID = trace.ID
+
+	expItem := &ExperimentItem{Item: item, TraceID: trace.ID}
+	start := time.Now()
+	output, taskErr := exp.Task(ctx, expItem)
+	latency := time.Since(start)
+	if taskErr != nil {
+		result.Err = fmt.Errorf("experiment task failed: %w", taskErr)
+	} else {
+		result.Output = output
+		// The created trace may still be queued, so the output is sent as
+		// an update of a new trace value
+		if _, err = l.Trace(&model.Trace{ID: trace.ID, Output: output}); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	_, err = l.CreateDatasetRunItem(ctx, &model.DatasetRunItem{
+		RunName:        exp.RunName,
+		RunDescription: exp.RunDescription,
+		Metadata:       exp.Metadata,
+		DatasetItemID:  item.ID,
+		TraceID:        trace.ID,
+		ObservationID:  expItem.ObservationID,
+	})
+	if err != nil && result.Err == nil {
+		result.Err = err
+	}
+
+	if taskErr != nil || len(exp.Evaluators) == 0 {
+		return result
+	}
+
+	sample := &evaluator.Sample{
+		Input:    item.Input,
+		Output:   output,
+		Expected: item.ExpectedOutput,
+		Metadata: evaluator.MetadataMap(item.Metadata),
+		Latency:  latency,
+	}
+
+	result.Scores, err = l.Evaluate(ctx, trace.ID, expItem.ObservationID, sample, exp.Evaluators...)
+	if err != nil && result.Err == nil {
+		result.Err = err
+	}
+
+	return result
+}
+
+func summarizeExperiment(runName string, items []ExperimentItemResult) *ExperimentResult {
+	result := &ExperimentResult{
+		RunName: runName,
+		Items:   items,
+		Scores:  map[string]ScoreAggregate{},
+	}
+
+	sums := map[string]float64{}
+	for _, item := range items {
+		if item.Err != nil {
+			result.Failed++
+		}
+
+		for _, score := range item.Scores {
+			agg, ok := result.Scores[score.Name]
+			if !ok {
+				agg = ScoreAggregate{Min: math.Inf(1), Max: math.Inf(-1)}
+			}
+			agg.Count++
+			agg.Min = math.Min(agg.Min, score.Value)
+			agg.Max = math.Max(agg.Max, score.Value)
+			sums[score.Name] += score.Value
+			result.Scores[score.Name] = agg
+		}
+	}
+
+	for name, agg := range result.Scores {
+		agg.Mean = sums[name] / float64(agg.Count)
+		result.Scores[name] = agg
+	}
+
+	return result
+}
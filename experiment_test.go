@@ -0,0 +1,107 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/optible/langfuse-go/evaluator"
+	"github.com/optible/langfuse-go/model"
+)
+
+func TestRunExperiment(t *testing.T) {
+	mu := sync.Mutex{}
+	runItems := []model.DatasetRunItem{}
+
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/public/dataset-items":
+			writeJSON(t, w, map[string]any{
+				"data": []map[string]any{
+					{"id": "item-1", "input": "2+2", "expectedOutput": "4", "status": "ACTIVE"},
+					{"id": "item-2", "input": "3+3", "expectedOutput": "6", "status": "ACTIVE"},
+					{"id": "item-3", "input": "1+1", "expectedOutput": "2", "status": "ARCHIVED"},
+					{"id": "item-4", "input": "fail", "status": "ACTIVE"},
+				},
+				"meta": map[string]any{"page": 1, "limit": 50, "totalItems": 4, "totalPages": 1},
+			})
+		case "/api/public/dataset-run-items":
+			var runItem model.DatasetRunItem
+			if err := json.NewDecoder(r.Body).Decode(&runItem); err != nil {
+				t.Errorf("failed to decode run item: %v", err)
+			}
+			mu.Lock()
+			runItems = append(runItems, runItem)
+			mu.Unlock()
+			writeJSON(t, w, runItem)
+		default:
+			writeJSON(t, w, map[string]any{"successes": []any{}, "errors": []any{}})
+		}
+	})
+
+	answers := map[string]string{"2+2": "4", "3+3": "7"}
+	result, err := l.RunExperiment(context.Background(), &Experiment{
+		DatasetName:    "math",
+		RunName:        "run-1",
+		MaxConcurrency: 2,
+		Task: func(ctx context.Context, item *ExperimentItem) (any, error) {
+			if item.Item.Input == "fail" {
+				return nil, errors.New("boom")
+			}
+			if item.TraceID == "" {
+				return nil, errors.New("missing trace ID")
+			}
+			item.ObservationID = "obs-" + item.Item.ID
+			return answers[item.Item.Input.(string)], nil
+		},
+		Evaluators: []evaluator.Evaluator{&evaluator.ExactMatch{}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	l.Flush(context.Background())
+
+	if len(result.Items) != 3 {
+		t.Fatalf("expected 3 active items, got %d", len(result.Items))
+	}
+	if result.Failed != 1 {
+		t.Errorf("expected 1 failed item, got %d", result.Failed)
+	}
+	if len(runItems) != 3 {
+		t.Errorf("expected 3 run items, got %d", len(runItems))
+	}
+	for _, runItem := range runItems {
+		if runItem.RunName != "run-1" || runItem.TraceID == "" {
+			t.Errorf("unexpected run item %+v", runItem)
+		}
+	}
+
+	agg := result.Scores["exact_match"]
+	if agg.Count != 2 || agg.Mean != 0.5 || agg.Min != 0 || agg.Max != 1 {
+		t.Errorf("unexpected aggregate %+v", agg)
+	}
+
+	first := result.Items[0]
+	if first.Scores[0].TraceID != first.TraceID || first.Scores[0].ObservationID != "obs-item-1" {
+		t.Errorf("expected score to be linked to trace and observation, got %+v", first.Scores[0])
+	}
+}
+
+func TestRunExperiment_Validation(t *testing.T) {
+	l := New(context.Background())
+
+	if _, err := l.RunExperiment(context.Background(), &Experiment{DatasetName: "d", RunName: "r"}); err == nil {
+		t.Error("expected error without task")
+	}
+
+	task := func(ctx context.Context, item *ExperimentItem) (any, error) { return nil, nil }
+	if _, err := l.RunExperiment(context.Background(), &Experiment{RunName: "r", Task: task}); err == nil {
+		t.Error("expected error without dataset name")
+	}
+	if _, err := l.RunExperiment(context.Background(), &Experiment{DatasetName: "d", Task: task}); err == nil {
+		t.Error("expected error without run name")
+	}
+}
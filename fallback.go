@@ -0,0 +1,70 @@
+package langfuse
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+
+	"github.com/optible/langfuse-go/model"
+	"github.com/optible/langfuse-go/promptfile"
+)
+
+// LoadFallbackPrompts registers the prompt files in fsys as fallbacks, for
+// example prompts bundled with //go:embed or pulled with langfuse-prompts.
+// When GetPrompt cannot fetch a prompt and no FallbackPrompt is given, it
+// returns the registered fallback with the requested name and version, or
+// with the requested label. Fallbacks without labels match any label.
+func (l *Langfuse) LoadFallbackPrompts(fsys fs.FS) error {
+	entries, err := promptfile.ReadFS(fsys)
+	if err != nil {
+		return fmt.Errorf("failed to load fallback prompts: %w", err)
+	}
+
+	for _, entry := range entries {
+		l.AddFallbackPrompt(entry.Prompt)
+	}
+	return nil
+}
+
+// AddFallbackPrompt registers a fallback prompt, see LoadFallbackPrompts
+func (l *Langfuse) AddFallbackPrompt(p *model.Prompt) {
+	l.fallbackMu.Lock()
+	defer l.fallbackMu.Unlock()
+
+	if l.fallbackPrompts == nil {
+		l.fallbackPrompts = map[string][]*model.Prompt{}
+	}
+	l.fallbackPrompts[p.GetName()] = append(l.fallbackPrompts[p.GetName()], p)
+}
+
+// fallbackPrompt returns the fallback for a prompt request, or nil if there
+// is none
+func (l *Langfuse) fallbackPrompt(name string, opts *GetPromptOptions) *model.Prompt {
+	if opts.FallbackPrompt != nil {
+		return opts.FallbackPrompt
+	}
+
+	l.fallbackMu.RLock()
+	defer l.fallbackMu.RUnlock()
+
+	label := defaultPromptLabel
+	if opts.Label != nil {
+		label = *opts.Label
+	}
+
+	for _, p := range l.fallbackPrompts[name] {
+		if opts.Version != nil {
+			if p.GetVersion() == *opts.Version {
+				return p
+			}
+			continue
+		}
+
+		labels := p.GetLabels()
+		if len(labels) == 0 || slices.Contains(labels, label) {
+			return p
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package langfuse
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+func newFailingTestClient(t *testing.T) *Langfuse {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+}
+
+func TestLoadFallbackPrompts(t *testing.T) {
+	l := newFailingTestClient(t)
+
+	err := l.LoadFallbackPrompts(fstest.MapFS{
+		"prompts/greeting.yaml":         {Data: []byte("name: greeting\ntype: text\nversion: 3\nlabels: [production]\nprompt: Hi {{name}}\n")},
+		"prompts/greeting-staging.yaml": {Data: []byte("name: greeting\ntype: text\nversion: 4\nlabels: [staging]\nprompt: Hello {{name}}\n")},
+		"prompts/support.json":          {Data: []byte(`{"name":"support","type":"chat","prompt":[{"role":"system","content":"Be brief."}]}`)},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	ctx := context.Background()
+	staging := "staging"
+	version := 3
+	canary := "canary"
+
+	tests := []struct {
+		name     string
+		prompt   string
+		opts     *GetPromptOptions
+		expected string
+	}{
+		{"default label", "greeting", nil, "Hi {{name}}"},
+		{"label", "greeting", &GetPromptOptions{Label: &staging}, "Hello {{name}}"},
+		{"version", "greeting", &GetPromptOptions{Version: &version}, "Hi {{name}}"},
+		{"without labels", "support", &GetPromptOptions{Label: &canary}, "Be brief."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt, err := l.GetPrompt(ctx, tt.prompt, tt.opts)
+			if err != nil {
+				t.Fatalf("expected fallback, got %v", err)
+			}
+			if !prompt.IsFallback {
+				t.Error("expected prompt to be marked as fallback")
+			}
+
+			content := ""
+			if prompt.IsText() {
+				content = prompt.TextPrompt.Prompt
+			} else {
+				content = prompt.ChatPrompt.Prompt[0].Content
+			}
+			if content != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, content)
+			}
+		})
+	}
+
+	// Unknown names, labels and versions still fail
+	version = 9
+	if _, err := l.GetPrompt(ctx, "greeting", &GetPromptOptions{Version: &version}); err == nil {
+		t.Error("expected error for unknown version")
+	}
+	if _, err := l.GetPrompt(ctx, "greeting", &GetPromptOptions{Label: &canary}); err == nil {
+		t.Error("expected error for unknown label")
+	}
+	if _, err := l.GetPrompt(ctx, "unknown", nil); err == nil {
+		t.Error("expected error for unknown prompt")
+	}
+}
+
+func TestGetPrompt_FallbackPromptOption(t *testing.T) {
+	l := newFailingTestClient(t)
+	l.AddFallbackPrompt(&model.Prompt{TextPrompt: &model.TextPrompt{Name: "greeting", Prompt: "registered"}})
+
+	option := &model.Prompt{TextPrompt: &model.TextPrompt{Name: "greeting", Prompt: "option"}}
+	prompt, err := l.GetPrompt(context.Background(), "greeting", &GetPromptOptions{FallbackPrompt: option})
+	if err != nil {
+		t.Fatalf("expected fallback, got %v", err)
+	}
+
+	if prompt.TextPrompt.Prompt != "option" || !prompt.IsFallback {
+		t.Errorf("expected the option fallback marked as fallback, got %+v", prompt)
+	}
+	if option.IsFallback {
+		t.Error("expected the option prompt not to be modified")
+	}
+}
@@ -0,0 +1,116 @@
+package langfuse
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const filePromptCacheExt = ".json"
+
+// FilePromptCache is a PromptCache storing each prompt as a JSON file in a
+// directory. Cached prompts survive restarts, and replicas sharing the
+// directory, e.g. on a shared volume, share the cache. Files are replaced
+// atomically, so concurrent readers never see partial writes.
+type FilePromptCache struct {
+	dir string
+}
+
+// NewFilePromptCache returns a cache storing prompts in dir, which is
+// created if it does not exist
+func NewFilePromptCache(dir string) (*FilePromptCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create prompt cache directory: %w", err)
+	}
+	return &FilePromptCache{dir: dir}, nil
+}
+
+// filePromptCacheEntry is the content of a cache file
+type filePromptCacheEntry struct {
+	Key string `json:"key"`
+	CachedPrompt
+}
+
+func (c *FilePromptCache) Get(_ context.Context, key string) (*CachedPrompt, error) {
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached prompt: %w", err)
+	}
+
+	var entry filePromptCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode cached prompt: %w", err)
+	}
+	if entry.Key != key {
+		return nil, nil
+	}
+
+	return &entry.CachedPrompt, nil
+}
+
+func (c *FilePromptCache) Set(_ context.Context, key string, entry *CachedPrompt) error {
+	data, err := json.Marshal(filePromptCacheEntry{Key: key, CachedPrompt: *entry})
+	if err != nil {
+		return fmt.Errorf("failed to encode prompt: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to cache prompt: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to cache prompt: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to cache prompt: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("failed to cache prompt: %w", err)
+	}
+	return nil
+}
+
+func (c *FilePromptCache) Delete(_ context.Context, key string) error {
+	if err := os.Remove(c.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete cached prompt: %w", err)
+	}
+	return nil
+}
+
+func (c *FilePromptCache) Clear(_ context.Context) error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to clear prompt cache: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), filePromptCacheExt) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, entry.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to clear prompt cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// path returns the file of a cache key. Keys contain prompt names, which
+// may contain any character, so files are named by the key hash.
+func (c *FilePromptCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+filePromptCacheExt)
+}
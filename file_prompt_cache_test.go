@@ -0,0 +1,99 @@
+package langfuse
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+func TestFilePromptCache(t *testing.T) {
+	ctx := context.Background()
+	c, err := NewFilePromptCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	if cached, err := c.Get(ctx, "prompt:a:l:production"); err != nil || cached != nil {
+		t.Fatalf("expected miss, got %+v, %v", cached, err)
+	}
+
+	expiresAt := time.Now().Add(time.Minute).Truncate(time.Millisecond)
+	entry := &CachedPrompt{
+		Prompt: &model.Prompt{ChatPrompt: &model.ChatPrompt{
+			Name:    "folder/a",
+			Version: 2,
+			Prompt:  []model.ChatMessage{{Role: "system", Content: "Hi {{name}}"}},
+		}},
+		ExpiresAt: expiresAt,
+	}
+	if err := c.Set(ctx, "prompt:folder/a:l:production", entry); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+
+	cached, err := c.Get(ctx, "prompt:folder/a:l:production")
+	if err != nil || cached == nil {
+		t.Fatalf("expected hit, got %+v, %v", cached, err)
+	}
+	if !cached.Prompt.IsChat() || cached.Prompt.GetVersion() != 2 || cached.Prompt.ChatPrompt.Prompt[0].Content != "Hi {{name}}" {
+		t.Errorf("unexpected prompt %+v", cached.Prompt.ChatPrompt)
+	}
+	if !cached.ExpiresAt.Equal(expiresAt) || cached.IsExpired() {
+		t.Errorf("unexpected expiration %v", cached.ExpiresAt)
+	}
+
+	if err := c.Delete(ctx, "prompt:folder/a:l:production"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if cached, _ := c.Get(ctx, "prompt:folder/a:l:production"); cached != nil {
+		t.Error("expected deleted prompt to be gone")
+	}
+
+	_ = c.Set(ctx, "one", entry)
+	_ = c.Set(ctx, "two", entry)
+	if err := c.Clear(ctx); err != nil {
+		t.Fatalf("failed to clear: %v", err)
+	}
+	files, _ := os.ReadDir(c.dir)
+	if len(files) != 0 {
+		t.Errorf("expected empty cache directory, got %d files", len(files))
+	}
+}
+
+func TestFilePromptCache_SurvivesRestart(t *testing.T) {
+	var offline atomic.Bool
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if offline.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(t, w, map[string]any{"type": "text", "name": "greeting", "version": 1, "prompt": "Hi"})
+	}
+	dir := t.TempDir()
+
+	c, err := NewFilePromptCache(dir)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	l := newTestClient(t, handler).WithPromptCache(c)
+	if _, err := l.GetPrompt(context.Background(), "greeting", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// A new client with the same directory is served from disk while Langfuse is down
+	offline.Store(true)
+	c, err = NewFilePromptCache(dir)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	restarted := newTestClient(t, handler).WithPromptCache(c)
+
+	prompt, err := restarted.GetPrompt(context.Background(), "greeting", nil)
+	if err != nil || prompt.TextPrompt.Prompt != "Hi" {
+		t.Errorf("expected cached prompt, got %+v, %v", prompt, err)
+	}
+}
@@ -5,4 +5,5 @@ go 1.21.1
 require (
 	github.com/google/uuid v1.6.0
 	github.com/henomis/restclientgo v1.2.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
@@ -56,41 +57,47 @@ func (c *Client) GetHost() string {
 	return c.host
 }
 
-// doRequest performs a raw HTTP request and returns the response body
-func (c *Client) doRequest(ctx context.Context, method, urlPath string) ([]byte, int, error) {
+// RawResponse is the response of a raw HTTP request
+type RawResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// DoRequest performs a raw HTTP request with an optional JSON body and
+// returns the response
+func (c *Client) DoRequest(ctx context.Context, method, urlPath string, reqBody []byte) (*RawResponse, error) {
 	fullURL := c.host + urlPath
 
-	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
+	var bodyReader io.Reader
+	if reqBody != nil {
+		bodyReader = bytes.NewReader(reqBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bodyReader)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Apply standard headers using the client's stored credentials
 	req.Header.Set("Authorization", basicAuth(c.publicKey, c.secretKey))
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept", ContentTypeJSON)
+	if reqBody != nil {
+		req.Header.Set("Content-Type", ContentTypeJSON)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to execute request: %w", err)
+		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	return body, resp.StatusCode, nil
-}
-
-// DoGetRequest performs a raw GET request and returns the response body
-func (c *Client) DoGetRequest(ctx context.Context, urlPath string) ([]byte, int, error) {
-	return c.doRequest(ctx, http.MethodGet, urlPath)
-}
-
-// DoDeleteRequest performs a raw DELETE request and returns the response body
-func (c *Client) DoDeleteRequest(ctx context.Context, urlPath string) ([]byte, int, error) {
-	return c.doRequest(ctx, http.MethodDelete, urlPath)
+	return &RawResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
 }
 
 func basicAuth(publicKey, secretKey string) string {
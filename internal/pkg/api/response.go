@@ -9,10 +9,11 @@ import (
 )
 
 type Response struct {
-	Code      int       `json:"-"`
-	RawBody   *string   `json:"-"`
-	Successes []Success `json:"successes"`
-	Errors    []Error   `json:"errors"`
+	Code      int                  `json:"-"`
+	Headers   restclientgo.Headers `json:"-"`
+	RawBody   *string              `json:"-"`
+	Successes []Success            `json:"successes"`
+	Errors    []Error              `json:"errors"`
 }
 
 type Success struct {
@@ -56,7 +57,8 @@ func (r *Response) Decode(body io.Reader) error {
 	return json.NewDecoder(body).Decode(r)
 }
 
-func (r *Response) SetHeaders(_ restclientgo.Headers) error {
+func (r *Response) SetHeaders(headers restclientgo.Headers) error {
+	r.Headers = headers
 	return nil
 }
 
@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"container/list"
+	"context"
 	"sync"
 	"time"
 )
@@ -16,17 +18,42 @@ func (e *Entry[T]) IsExpired() bool {
 	return time.Now().After(e.ExpiresAt)
 }
 
-// Cache is a thread-safe cache with TTL support
+// Stats contains cache usage statistics
+type Stats struct {
+	// Hits counts lookups that found an entry, expired or not
+	Hits uint64
+	// Misses counts lookups that found no entry
+	Misses uint64
+	// Evictions counts entries removed to stay within the max entries
+	Evictions uint64
+	// Expirations counts expired entries removed by Cleanup
+	Expirations uint64
+	// Size is the number of entries in the cache
+	Size int
+}
+
+// item is an entry in the recency list
+type item[T any] struct {
+	key   string
+	entry *Entry[T]
+}
+
+// Cache is a thread-safe cache with TTL support and optional LRU eviction
 type Cache[T any] struct {
-	entries map[string]*Entry[T]
-	mu      sync.RWMutex
-	ttl     time.Duration
+	entries    map[string]*list.Element
+	recency    *list.List
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	maxStale   time.Duration
+	stats      Stats
 }
 
 // New creates a new cache with the specified TTL
 func New[T any](ttl time.Duration) *Cache[T] {
 	return &Cache[T]{
-		entries: make(map[string]*Entry[T]),
+		entries: make(map[string]*list.Element),
+		recency: list.New(),
 		ttl:     ttl,
 	}
 }
@@ -34,11 +61,11 @@ func New[T any](ttl time.Duration) *Cache[T] {
 // Get retrieves an item from the cache.
 // Returns the value, whether it was found, and whether it's expired (stale).
 func (c *Cache[T]) Get(key string) (T, bool, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	entry, ok := c.entries[key]
-	if !ok {
+	entry := c.lookup(key)
+	if entry == nil {
 		var zero T
 		return zero, false, false
 	}
@@ -46,15 +73,40 @@ func (c *Cache[T]) Get(key string) (T, bool, bool) {
 	return entry.Value, true, entry.IsExpired()
 }
 
+// GetEntry retrieves an item from the cache with its expiration time.
+// Returns nil if the item is not in the cache.
+func (c *Cache[T]) GetEntry(key string) *Entry[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.lookup(key)
+	if entry == nil {
+		return nil
+	}
+
+	copied := *entry
+	return &copied
+}
+
+// lookup returns the entry for key and marks it as recently used
+func (c *Cache[T]) lookup(key string) *Entry[T] {
+	elem, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil
+	}
+
+	c.stats.Hits++
+	c.recency.MoveToFront(elem)
+	return elem.Value.(*item[T]).entry
+}
+
 // Set stores an item in the cache
 func (c *Cache[T]) Set(key string, value T) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.entries[key] = &Entry[T]{
-		Value:     value,
-		ExpiresAt: time.Now().Add(c.ttl),
-	}
+	c.set(key, value, c.ttl)
 }
 
 // SetWithTTL stores an item in the cache with a custom TTL
@@ -62,10 +114,40 @@ func (c *Cache[T]) SetWithTTL(key string, value T, ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.entries[key] = &Entry[T]{
+	c.set(key, value, ttl)
+}
+
+func (c *Cache[T]) set(key string, value T, ttl time.Duration) {
+	entry := &Entry[T]{
 		Value:     value,
 		ExpiresAt: time.Now().Add(ttl),
 	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*item[T]).entry = entry
+		c.recency.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.recency.PushFront(&item[T]{key: key, entry: entry})
+	c.evict()
+}
+
+// evict removes the least recently used entries above the max entries
+func (c *Cache[T]) evict() {
+	if c.maxEntries <= 0 {
+		return
+	}
+
+	for len(c.entries) > c.maxEntries {
+		c.remove(c.recency.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *Cache[T]) remove(elem *list.Element) {
+	c.recency.Remove(elem)
+	delete(c.entries, elem.Value.(*item[T]).key)
 }
 
 // Delete removes an item from the cache
@@ -73,7 +155,9 @@ func (c *Cache[T]) Delete(key string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	delete(c.entries, key)
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
 }
 
 // Clear removes all items from the cache
@@ -81,30 +165,57 @@ func (c *Cache[T]) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.entries = make(map[string]*Entry[T])
+	c.entries = make(map[string]*list.Element)
+	c.recency.Init()
 }
 
-// Cleanup removes expired entries from the cache
+// Cleanup removes entries that expired more than the max stale duration ago
 func (c *Cache[T]) Cleanup() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	now := time.Now()
-	for key, entry := range c.entries {
-		if now.After(entry.ExpiresAt) {
-			delete(c.entries, key)
+	cutoff := time.Now().Add(-c.maxStale)
+	for _, elem := range c.entries {
+		if cutoff.After(elem.Value.(*item[T]).entry.ExpiresAt) {
+			c.remove(elem)
+			c.stats.Expirations++
+		}
+	}
+}
+
+// RunJanitor calls Cleanup every interval until ctx is done
+func (c *Cache[T]) RunJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Cleanup()
 		}
 	}
 }
 
 // Size returns the number of items in the cache
 func (c *Cache[T]) Size() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	return len(c.entries)
 }
 
+// Stats returns the cache usage statistics
+func (c *Cache[T]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = len(c.entries)
+	return stats
+}
+
 // SetTTL updates the default TTL for new entries
 func (c *Cache[T]) SetTTL(ttl time.Duration) {
 	c.mu.Lock()
@@ -115,8 +226,27 @@ func (c *Cache[T]) SetTTL(ttl time.Duration) {
 
 // GetTTL returns the default TTL
 func (c *Cache[T]) GetTTL() time.Duration {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	return c.ttl
 }
+
+// SetMaxEntries limits the number of entries, evicting the least recently
+// used entries above the limit. Zero or less means no limit.
+func (c *Cache[T]) SetMaxEntries(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxEntries = n
+	c.evict()
+}
+
+// SetMaxStale sets how long expired entries are kept before Cleanup removes
+// them, so that they can still be served while they are refreshed
+func (c *Cache[T]) SetMaxStale(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxStale = d
+}
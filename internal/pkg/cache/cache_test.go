@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"testing"
 	"time"
 )
@@ -169,3 +170,111 @@ func TestCache_ConcurrentAccess(t *testing.T) {
 		<-done
 	}
 }
+
+func TestCache_GetEntry(t *testing.T) {
+	c := New[string](time.Minute)
+
+	if entry := c.GetEntry("key1"); entry != nil {
+		t.Errorf("expected no entry, got %+v", entry)
+	}
+
+	before := time.Now()
+	c.SetWithTTL("key1", "value1", time.Hour)
+
+	entry := c.GetEntry("key1")
+	if entry == nil || entry.Value != "value1" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	if entry.ExpiresAt.Before(before.Add(time.Hour)) || entry.IsExpired() {
+		t.Errorf("unexpected expiration %v", entry.ExpiresAt)
+	}
+}
+
+func TestCache_MaxEntries(t *testing.T) {
+	c := New[string](time.Minute)
+	c.SetMaxEntries(2)
+
+	c.Set("key1", "value1")
+	c.Set("key2", "value2")
+
+	// Using key1 makes key2 the least recently used
+	c.Get("key1")
+	c.Set("key3", "value3")
+
+	if _, found, _ := c.Get("key2"); found {
+		t.Error("expected key2 to be evicted")
+	}
+	if _, found, _ := c.Get("key1"); !found {
+		t.Error("expected key1 to still exist")
+	}
+	if c.Size() != 2 {
+		t.Errorf("expected size 2, got %d", c.Size())
+	}
+
+	c.SetMaxEntries(1)
+	if _, found, _ := c.Get("key1"); !found || c.Size() != 1 {
+		t.Error("expected only the most recently used key1 to remain")
+	}
+}
+
+func TestCache_Stats(t *testing.T) {
+	c := New[string](time.Minute)
+	c.SetMaxEntries(1)
+
+	c.Set("key1", "value1")
+	c.Get("key1")
+	c.GetEntry("key1")
+	c.Get("missing")
+	c.Set("key2", "value2")
+	c.SetWithTTL("key2", "value2", -time.Second)
+	c.Cleanup()
+
+	stats := c.Stats()
+	want := Stats{Hits: 2, Misses: 1, Evictions: 1, Expirations: 1, Size: 0}
+	if stats != want {
+		t.Errorf("expected %+v, got %+v", want, stats)
+	}
+}
+
+func TestCache_MaxStale(t *testing.T) {
+	c := New[string](time.Minute)
+	c.SetMaxStale(time.Hour)
+
+	c.SetWithTTL("stale", "value1", -time.Minute)
+	c.SetWithTTL("old", "value2", -2*time.Hour)
+	c.Cleanup()
+
+	if _, found, expired := c.Get("stale"); !found || !expired {
+		t.Error("expected stale entry to be kept")
+	}
+	if _, found, _ := c.Get("old"); found {
+		t.Error("expected old entry to be removed")
+	}
+}
+
+func TestCache_RunJanitor(t *testing.T) {
+	c := New[string](time.Millisecond)
+	c.Set("key1", "value1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.RunJanitor(ctx, 5*time.Millisecond)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for c.Size() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if c.Size() != 0 {
+		t.Error("expected janitor to remove the expired entry")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("expected janitor to stop when the context is done")
+	}
+}
@@ -0,0 +1,269 @@
+// Package mustache implements the subset of the mustache template language
+// used by Langfuse prompts: variables, sections, inverted sections and
+// comments. Like the Langfuse SDKs, values are never HTML-escaped.
+package mustache
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	openTag       = "{{"
+	closeTag      = "}}"
+	tripleOpenTag = "{{{"
+	tripleClose   = "}}}"
+)
+
+type nodeKind int
+
+const (
+	textNode nodeKind = iota
+	variableNode
+	sectionNode
+	invertedNode
+)
+
+type node struct {
+	kind     nodeKind
+	text     string // literal text, or the raw tag for variables
+	name     string
+	children []*node
+}
+
+// Template is a parsed mustache template
+type Template struct {
+	nodes []*node
+}
+
+type tokenKind int
+
+const (
+	textToken tokenKind = iota
+	variableToken
+	sectionToken
+	invertedToken
+	closeToken
+	commentToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	name string
+}
+
+// Parse parses a mustache template
+func Parse(source string) (*Template, error) {
+	return parse(source, false)
+}
+
+// ParseLenient parses a mustache template like Parse, but keeps malformed
+// tags, such as a stray "{{" or an unclosed section, as literal text instead
+// of failing
+func ParseLenient(source string) *Template {
+	t, _ := parse(source, true)
+	return t
+}
+
+func parse(source string, lenient bool) (*Template, error) {
+	tokens, err := tokenize(source, lenient)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens = stripStandalone(tokens)
+
+	root := &node{}
+	stack := []*node{root}
+	for _, tok := range tokens {
+		parent := stack[len(stack)-1]
+
+		switch tok.kind {
+		case textToken:
+			if tok.text != "" {
+				parent.children = append(parent.children, &node{kind: textNode, text: tok.text})
+			}
+		case variableToken:
+			parent.children = append(parent.children, &node{kind: variableNode, text: tok.text, name: tok.name})
+		case sectionToken, invertedToken:
+			kind := sectionNode
+			if tok.kind == invertedToken {
+				kind = invertedNode
+			}
+			section := &node{kind: kind, text: tok.text, name: tok.name}
+			parent.children = append(parent.children, section)
+			stack = append(stack, section)
+		case closeToken:
+			if len(stack) == 1 || parent.name != tok.name {
+				if lenient {
+					parent.children = append(parent.children, &node{kind: textNode, text: tok.text})
+					continue
+				}
+				return nil, fmt.Errorf("unexpected closing tag {{/%s}}", tok.name)
+			}
+			stack = stack[:len(stack)-1]
+		case commentToken:
+		}
+	}
+
+	if len(stack) > 1 && !lenient {
+		return nil, fmt.Errorf("unclosed section {{#%s}}", stack[len(stack)-1].name)
+	}
+
+	// Unclosed sections are replaced by their tag as text and their content
+	for len(stack) > 1 {
+		section, parent := stack[len(stack)-1], stack[len(stack)-2]
+		parent.children = append(parent.children[:len(parent.children)-1], &node{kind: textNode, text: section.text})
+		parent.children = append(parent.children, section.children...)
+		stack = stack[:len(stack)-1]
+	}
+
+	return &Template{nodes: root.children}, nil
+}
+
+func tokenize(source string, lenient bool) ([]token, error) {
+	var tokens []token
+
+	for {
+		start := strings.Index(source, openTag)
+		if start < 0 {
+			tokens = append(tokens, token{kind: textToken, text: source})
+			return tokens, nil
+		}
+
+		tokens = append(tokens, token{kind: textToken, text: source[:start]})
+
+		closing := closeTag
+		contentStart := start + len(openTag)
+		if strings.HasPrefix(source[start:], tripleOpenTag) {
+			closing = tripleClose
+			contentStart = start + len(tripleOpenTag)
+		}
+
+		end := strings.Index(source[contentStart:], closing)
+		if end < 0 {
+			if lenient {
+				tokens = append(tokens, token{kind: textToken, text: source[start:]})
+				return tokens, nil
+			}
+			return nil, fmt.Errorf("unclosed tag at offset %d", start)
+		}
+		end += contentStart
+
+		raw := source[start : end+len(closing)]
+		content := strings.TrimSpace(source[contentStart:end])
+		source = source[end+len(closing):]
+
+		if closing == tripleClose {
+			tokens = append(tokens, token{kind: variableToken, text: raw, name: content})
+			continue
+		}
+
+		tok := token{kind: variableToken, text: raw, name: content}
+		if content != "" {
+			sigil := content[0]
+			name := strings.TrimSpace(content[1:])
+			switch sigil {
+			case '#':
+				tok = token{kind: sectionToken, text: raw, name: name}
+			case '^':
+				tok = token{kind: invertedToken, text: raw, name: name}
+			case '/':
+				tok = token{kind: closeToken, text: raw, name: name}
+			case '!':
+				tok = token{kind: commentToken, text: raw}
+			case '&':
+				tok = token{kind: variableToken, text: raw, name: name}
+			}
+		}
+
+		if tok.kind != commentToken && tok.name == "" {
+			if lenient {
+				tokens = append(tokens, token{kind: textToken, text: raw})
+				continue
+			}
+			return nil, fmt.Errorf("empty tag %s", raw)
+		}
+
+		tokens = append(tokens, tok)
+	}
+}
+
+// stripStandalone removes the lines containing only a section, inverted
+// section, closing or comment tag, as the mustache specification requires
+func stripStandalone(tokens []token) []token {
+	// startsLine tracks which text tokens begin at the start of a line
+	startsLine := make([]bool, len(tokens))
+	startsLine[0] = true
+
+	for i, tok := range tokens {
+		if tok.kind == textToken || tok.kind == variableToken {
+			continue
+		}
+
+		// Tags are always surrounded by text tokens, see tokenize
+		prev, next := &tokens[i-1], &tokens[i+1]
+
+		lineStart := strings.LastIndex(prev.text, "\n") + 1
+		if lineStart == 0 && !startsLine[i-1] {
+			continue
+		}
+		if strings.TrimSpace(prev.text[lineStart:]) != "" {
+			continue
+		}
+
+		rest := next.text
+		if lineEnd := strings.Index(next.text, "\n"); lineEnd >= 0 {
+			rest = next.text[:lineEnd+1]
+		} else if i+1 != len(tokens)-1 {
+			continue
+		}
+		if strings.TrimSpace(rest) != "" {
+			continue
+		}
+
+		prev.text = prev.text[:lineStart]
+		next.text = next.text[len(rest):]
+		startsLine[i+1] = true
+	}
+
+	return tokens
+}
+
+// Variables returns the names of the variables and sections used by the
+// template, in order of first appearance. Names used inside sections are
+// included, even though they may be resolved from the section value, so that
+// the template's needs are not under-reported.
+func (t *Template) Variables() []string {
+	var names []string
+	seen := map[string]bool{}
+
+	var walk func(nodes []*node)
+	walk = func(nodes []*node) {
+		for _, n := range nodes {
+			if n.kind == textNode {
+				continue
+			}
+
+			if name := rootName(n.name); name != "." && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+			walk(n.children)
+		}
+	}
+	walk(t.nodes)
+
+	return names
+}
+
+// rootName returns the first segment of a dotted name
+func rootName(name string) string {
+	if name == "." {
+		return name
+	}
+
+	root, _, _ := strings.Cut(name, ".")
+	return root
+}
@@ -0,0 +1,141 @@
+package mustache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func render(t *testing.T, source string, data map[string]any) *Result {
+	t.Helper()
+
+	tmpl, err := Parse(source)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", source, err)
+	}
+	return tmpl.Render(data)
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		data     map[string]any
+		expected string
+	}{
+		{"variable", "Hello {{name}}!", map[string]any{"name": "Alice"}, "Hello Alice!"},
+		{"whitespace", "Hello {{ name }}!", map[string]any{"name": "Alice"}, "Hello Alice!"},
+		{"no escaping", "{{html}}", map[string]any{"html": "<b>&</b>"}, "<b>&</b>"},
+		{"triple braces", "{{{html}}}", map[string]any{"html": "<b>"}, "<b>"},
+		{"ampersand", "{{& html}}", map[string]any{"html": "<b>"}, "<b>"},
+		{"number", "{{n}} {{f}}", map[string]any{"n": 3, "f": 0.5}, "3 0.5"},
+		{"bool", "{{b}}", map[string]any{"b": true}, "true"},
+		{"structured", "{{v}}", map[string]any{"v": map[string]any{"a": 1}}, `{"a":1}`},
+		{"dotted", "{{user.name}}", map[string]any{"user": map[string]any{"name": "Bob"}}, "Bob"},
+		{"comment", "a{{! ignored }}b", nil, "ab"},
+		{"section list", "{{#items}}- {{.}}\n{{/items}}", map[string]any{"items": []string{"a", "b"}}, "- a\n- b\n"},
+		{
+			"section maps",
+			"{{#users}}{{name}} {{/users}}",
+			map[string]any{"users": []any{map[string]any{"name": "A"}, map[string]any{"name": "B"}}},
+			"A B ",
+		},
+		{"section outer lookup", "{{#items}}{{.}}{{sep}}{{/items}}", map[string]any{"items": []int{1, 2}, "sep": ","}, "1,2,"},
+		{"section false", "{{#show}}x{{/show}}", map[string]any{"show": false}, ""},
+		{"section empty list", "{{#items}}x{{/items}}", map[string]any{"items": []string{}}, ""},
+		{"inverted", "{{^items}}none{{/items}}", map[string]any{"items": []string{}}, "none"},
+		{"inverted missing", "{{^items}}none{{/items}}", nil, "none"},
+		{"standalone lines", "Start\n  {{#show}}\nShown\n  {{/show}}\nEnd", map[string]any{"show": true}, "Start\nShown\nEnd"},
+		{"missing kept", "Hi {{ name }} and {{other}}", map[string]any{"name": "A"}, "Hi A and {{other}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := render(t, tt.source, tt.data)
+			if result.Output != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result.Output)
+			}
+		})
+	}
+}
+
+func TestRender_MissingAndUnused(t *testing.T) {
+	data := map[string]any{"name": "A", "items": []string{"x"}, "extra": 1, "also": 2}
+	result := render(t, "{{name}} {{role}} {{#items}}{{.}}{{/items}} {{role}}", data)
+
+	if !reflect.DeepEqual(result.Missing, []string{"role"}) {
+		t.Errorf("unexpected missing variables %v", result.Missing)
+	}
+	if unused := result.Unused(data); !reflect.DeepEqual(unused, []string{"also", "extra"}) {
+		t.Errorf("unexpected unused variables %v", unused)
+	}
+}
+
+func TestRender_MissingSections(t *testing.T) {
+	data := map[string]any{"greeting": "Hi"}
+	result := render(t, "{{#user}}{{greeting}} {{name}}{{/user}}{{^admin}}{{role}}{{/admin}}", data)
+
+	if !reflect.DeepEqual(result.Missing, []string{"user", "name", "admin", "role"}) {
+		t.Errorf("unexpected missing variables %v", result.Missing)
+	}
+	if unused := result.Unused(data); len(unused) != 0 {
+		t.Errorf("unexpected unused variables %v", unused)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, source := range []string{
+		"Hello {{name",
+		"{{#a}}unclosed",
+		"{{/a}}",
+		"{{#a}}{{/b}}",
+		"{{}}",
+	} {
+		if _, err := Parse(source); err == nil {
+			t.Errorf("expected error for %q", source)
+		}
+	}
+}
+
+func TestTemplate_VariablesInSections(t *testing.T) {
+	tmpl, err := Parse("{{#show}}Hi {{name}}{{^admin}}{{role}}{{/admin}}{{/show}}")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []string{"show", "name", "admin", "role"}
+	if vars := tmpl.Variables(); !reflect.DeepEqual(vars, expected) {
+		t.Errorf("expected %v, got %v", expected, vars)
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	data := map[string]any{"name": "Ann", "a": "x"}
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"Hi {{name}}, use {{ to open", "Hi Ann, use {{ to open"},
+		{"{{}} {{name}}", "{{}} Ann"},
+		{"{{/a}} {{name}}", "{{/a}} Ann"},
+		{"{{#a}}{{name}}{{/b}}", "{{#a}}Ann{{/b}}"},
+		{"{{#missing}}{{name}} rest", "{{#missing}}Ann rest"},
+	}
+
+	for _, tt := range tests {
+		if output := ParseLenient(tt.source).Render(data).Output; output != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.source, tt.expected, output)
+		}
+	}
+}
+
+func TestTemplate_Variables(t *testing.T) {
+	tmpl, err := Parse("{{a}} {{ b.c }} {{#list}}{{inner}}{{/list}} {{^d}}{{/d}} {{a}}")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []string{"a", "b", "list", "inner", "d"}
+	if vars := tmpl.Variables(); !reflect.DeepEqual(vars, expected) {
+		t.Errorf("expected %v, got %v", expected, vars)
+	}
+}
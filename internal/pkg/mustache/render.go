@@ -0,0 +1,234 @@
+package mustache
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Result is the outcome of rendering a template
+type Result struct {
+	Output string
+
+	// Missing lists the variables and section names that could not be
+	// resolved, in order of first appearance. The variables inside a section
+	// whose name could not be resolved are included as well.
+	Missing []string
+
+	// Used contains the top-level data keys referenced by the template.
+	Used map[string]bool
+}
+
+// Unused returns the sorted top-level data keys not referenced by the template
+func (r *Result) Unused(data map[string]any) []string {
+	var unused []string
+	for key := range data {
+		if !r.Used[key] {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// Render renders the template with data. Variables that cannot be resolved
+// are kept as written in the template, so that a partially filled prompt is
+// recognizable; sections with unresolvable names are skipped.
+func (t *Template) Render(data map[string]any) *Result {
+	r := &renderer{
+		root:    data,
+		used:    map[string]bool{},
+		missing: map[string]bool{},
+	}
+
+	var sb strings.Builder
+	r.render(&sb, t.nodes, []any{data})
+
+	return &Result{
+		Output:  sb.String(),
+		Missing: r.missingOrder,
+		Used:    r.used,
+	}
+}
+
+type renderer struct {
+	root         map[string]any
+	used         map[string]bool
+	missing      map[string]bool
+	missingOrder []string
+}
+
+func (r *renderer) render(sb *strings.Builder, nodes []*node, stack []any) {
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode:
+			sb.WriteString(n.text)
+
+		case variableNode:
+			value, ok := r.lookup(n.name, stack)
+			if !ok {
+				r.markMissing(n.name)
+				sb.WriteString(n.text)
+				continue
+			}
+			sb.WriteString(format(value))
+
+		case sectionNode:
+			value, ok := r.lookup(n.name, stack)
+			if !ok {
+				r.markMissing(n.name)
+				r.skip(n.children, stack, true)
+				continue
+			}
+			if !truthy(value) {
+				r.skip(n.children, stack, false)
+				continue
+			}
+
+			rv := reflect.ValueOf(value)
+			if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+				for i := 0; i < rv.Len(); i++ {
+					r.render(sb, n.children, append(stack, rv.Index(i).Interface()))
+				}
+				continue
+			}
+			r.render(sb, n.children, append(stack, value))
+
+		case invertedNode:
+			value, ok := r.lookup(n.name, stack)
+			if !ok {
+				r.markMissing(n.name)
+			}
+			if !ok || !truthy(value) {
+				r.render(sb, n.children, stack)
+			} else {
+				r.skip(n.children, stack, false)
+			}
+		}
+	}
+}
+
+// lookup resolves a possibly dotted name against the context stack,
+// innermost context first
+func (r *renderer) lookup(name string, stack []any) (any, bool) {
+	if name == "." {
+		return stack[len(stack)-1], true
+	}
+
+	parts := strings.Split(name, ".")
+	for i := len(stack) - 1; i >= 0; i-- {
+		value, ok := field(stack[i], parts[0])
+		if !ok {
+			continue
+		}
+
+		if i == 0 {
+			r.used[parts[0]] = true
+		}
+
+		for _, part := range parts[1:] {
+			if value, ok = field(value, part); !ok {
+				return nil, false
+			}
+		}
+		return value, true
+	}
+
+	return nil, false
+}
+
+func (r *renderer) markMissing(name string) {
+	if !r.missing[name] {
+		r.missing[name] = true
+		r.missingOrder = append(r.missingOrder, name)
+	}
+}
+
+// skip resolves the names used in the nodes of a section that is not
+// rendered, so that their values count as used. If the section is skipped
+// because its name is missing, the names that cannot be resolved are marked
+// missing as well.
+func (r *renderer) skip(nodes []*node, stack []any, markMissing bool) {
+	for _, n := range nodes {
+		if n.kind == textNode || n.name == "." {
+			continue
+		}
+		if _, ok := r.lookup(n.name, stack); !ok && markMissing {
+			r.markMissing(n.name)
+		}
+		r.skip(n.children, stack, markMissing)
+	}
+}
+
+// field returns the value of key in a map with string keys
+func field(context any, key string) (any, bool) {
+	if m, ok := context.(map[string]any); ok {
+		value, found := m[key]
+		return value, found
+	}
+
+	rv := reflect.ValueOf(context)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+
+	value := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()))
+	if !value.IsValid() {
+		return nil, false
+	}
+	return value.Interface(), true
+}
+
+// truthy reports whether a section with value should be rendered
+func truthy(value any) bool {
+	if value == nil {
+		return false
+	}
+
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len() > 0
+	case reflect.Pointer, reflect.Interface:
+		return !rv.IsNil()
+	default:
+		return true
+	}
+}
+
+// format returns the text representation of a variable value. Structured
+// values are encoded as JSON.
+func format(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
@@ -0,0 +1,107 @@
+// Package singleflight deduplicates concurrent calls for the same key.
+package singleflight
+
+import "sync"
+
+type call[T any] struct {
+	wg  sync.WaitGroup
+	val T
+	err error
+
+	// dups counts the callers waiting for the call and chans receives the
+	// result for DoChan callers, both guarded by Group.mu
+	dups  int
+	chans []chan<- Result[T]
+}
+
+// Result is the result of a call, delivered by DoChan
+type Result[T any] struct {
+	Val T
+	Err error
+}
+
+// Group runs at most one call per key at a time. Callers asking for a key
+// while a call is in flight wait for it and share its result.
+type Group[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+// Do runs fn for key, or waits for the call in flight for key, and returns
+// its result
+func (g *Group[T]) Do(key string, fn func() (T, error)) (T, error) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, c.err
+	}
+	c := g.start(key)
+	g.mu.Unlock()
+
+	g.run(key, c, fn)
+	return c.val, c.err
+}
+
+// DoChan is like Do but returns a channel that receives the result, so that
+// callers can stop waiting, e.g. when their context is done. The call
+// continues and its result is shared with the other callers.
+func (g *Group[T]) DoChan(key string, fn func() (T, error)) <-chan Result[T] {
+	ch := make(chan Result[T], 1)
+
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		c.chans = append(c.chans, ch)
+		g.mu.Unlock()
+		return ch
+	}
+	c := g.start(key)
+	c.chans = append(c.chans, ch)
+	g.mu.Unlock()
+
+	go g.run(key, c, fn)
+	return ch
+}
+
+// Go runs fn for key in a new goroutine, unless a call for key is already in
+// flight. It reports whether fn was started.
+func (g *Group[T]) Go(key string, fn func() (T, error)) bool {
+	g.mu.Lock()
+	if _, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		return false
+	}
+	c := g.start(key)
+	g.mu.Unlock()
+
+	go g.run(key, c, fn)
+	return true
+}
+
+// start registers a call for key, the caller must hold g.mu
+func (g *Group[T]) start(key string) *call[T] {
+	if g.calls == nil {
+		g.calls = make(map[string]*call[T])
+	}
+
+	c := &call[T]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	return c
+}
+
+func (g *Group[T]) run(key string, c *call[T], fn func() (T, error)) {
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		for _, ch := range c.chans {
+			ch <- Result[T]{Val: c.val, Err: c.err}
+		}
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	c.val, c.err = fn()
+}
@@ -0,0 +1,84 @@
+package singleflight
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	var g Group[int]
+	release := make(chan struct{})
+
+	started := g.Go("key", func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	if !started {
+		t.Fatal("expected first call to start")
+	}
+
+	if g.Go("key", func() (int, error) { return 2, nil }) {
+		t.Error("expected second call not to start while the first is in flight")
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = g.Do("key", func() (int, error) { return 3, nil })
+		}(i)
+	}
+
+	// Release the call once all callers wait for it
+	for g.waiting("key") < len(results) {
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+
+	for _, result := range results {
+		if result != 1 {
+			t.Errorf("expected to share the in-flight result, got %d", result)
+		}
+	}
+
+	// Once done, the next call runs again
+	if value, _ := g.Do("key", func() (int, error) { return 4, nil }); value != 4 {
+		t.Errorf("expected a new call, got %d", value)
+	}
+}
+
+func (g *Group[T]) waiting(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if c, ok := g.calls[key]; ok {
+		return c.dups
+	}
+	return 0
+}
+
+func TestGroup_DoChan(t *testing.T) {
+	var g Group[int]
+	release := make(chan struct{})
+
+	first := g.DoChan("key", func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	second := g.DoChan("key", func() (int, error) { return 2, nil })
+
+	if g.waiting("key") != 1 {
+		t.Fatal("expected second call to wait for the first")
+	}
+	close(release)
+
+	for _, ch := range []<-chan Result[int]{first, second} {
+		if res := <-ch; res.Val != 1 || res.Err != nil {
+			t.Errorf("expected to share the first result, got %+v", res)
+		}
+	}
+}
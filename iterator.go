@@ -0,0 +1,93 @@
+package langfuse
+
+import (
+	"context"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+const (
+	firstPage = 1
+)
+
+// pageFetcher fetches a single page of a paginated list endpoint
+type pageFetcher[T any] func(ctx context.Context, page int) ([]T, model.PaginationMeta, error)
+
+// Iterator walks all items of a paginated list endpoint, fetching
+// subsequent pages on demand.
+//
+//	it := l.IterObservations(&langfuse.ObservationFilter{TraceID: traceID})
+//	for it.Next(ctx) {
+//		obs := it.Current()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	fetch   pageFetcher[T]
+	page    int
+	items   []T
+	index   int
+	current T
+	err     error
+	done    bool
+}
+
+func newIterator[T any](startPage int, fetch pageFetcher[T]) *Iterator[T] {
+	if startPage < firstPage {
+		startPage = firstPage
+	}
+
+	return &Iterator[T]{
+		fetch: fetch,
+		page:  startPage,
+	}
+}
+
+// Next advances the iterator to the next item, fetching the next page when
+// the current one is exhausted. It returns false when there are no more items
+// or an error occurred; use Err to tell the two apart.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for it.index >= len(it.items) {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		items, meta, err := it.fetch(ctx, it.page)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.items = items
+		it.index = 0
+		it.done = it.page >= meta.TotalPages
+		it.page++
+	}
+
+	it.current = it.items[it.index]
+	it.index++
+
+	return true
+}
+
+// Current returns the item the iterator is positioned on
+func (it *Iterator[T]) Current() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All consumes the remaining items and returns them
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	for it.Next(ctx) {
+		items = append(items, it.Current())
+	}
+
+	return items, it.Err()
+}
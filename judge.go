@@ -0,0 +1,179 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/optible/langfuse-go/evaluator"
+	"github.com/optible/langfuse-go/model"
+)
+
+// LLMRequest is the compiled judge prompt handed to an LLMCaller.
+// Exactly one of Prompt and Messages is set, depending on the prompt type.
+type LLMRequest struct {
+	Prompt   string
+	Messages []model.ChatMessage
+
+	// Config is the config of the judge prompt, e.g. model and temperature.
+	Config any
+}
+
+// LLMResponse is the completion returned by an LLMCaller.
+type LLMResponse struct {
+	Content string
+
+	// Model, ModelParameters and Usage are recorded on the judge generation.
+	Model           string
+	ModelParameters any
+	Usage           model.Usage
+}
+
+// LLMCaller calls the language model used as a judge.
+type LLMCaller interface {
+	Call(ctx context.Context, req *LLMRequest) (*LLMResponse, error)
+}
+
+// LLMJudge is an evaluator that grades outputs using a judge prompt stored in
+// Langfuse prompt management. The prompt is compiled with the variables
+// "input", "output" and "expected" as well as the values of the sample
+// metadata. The model must answer with a JSON object such as
+//
+//	{"score": 0.8, "reasoning": "The answer is correct but verbose."}
+//
+// The judge call is recorded as a generation in the evaluated trace and the
+// reasoning is used as the score comment. Chat judge prompts with
+// placeholders are rejected, as there are no messages to fill them with.
+type LLMJudge struct {
+	client *Langfuse
+	caller LLMCaller
+
+	// PromptName is the name of the judge prompt.
+	PromptName string
+
+	// PromptOptions selects the version or label of the judge prompt.
+	PromptOptions *GetPromptOptions
+
+	// ScoreName is the name of the resulting score. Defaults to PromptName.
+	ScoreName string
+}
+
+// NewLLMJudge creates an LLM-as-a-judge evaluator using the named prompt
+func (l *Langfuse) NewLLMJudge(promptName string, caller LLMCaller) *LLMJudge {
+	return &LLMJudge{
+		client:     l,
+		caller:     caller,
+		PromptName: promptName,
+	}
+}
+
+func (j *LLMJudge) Evaluate(ctx context.Context, s *evaluator.Sample) ([]model.Score, error) {
+	if j.caller == nil {
+		return nil, fmt.Errorf("LLM caller is required")
+	}
+
+	prompt, err := j.client.GetPrompt(ctx, j.PromptName, j.PromptOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get judge prompt: %w", err)
+	}
+
+	variables := map[string]any{}
+	for key, value := range s.Metadata {
+		variables[key] = value
+	}
+	variables["input"] = evaluator.Stringify(s.Input)
+	variables["output"] = evaluator.Stringify(s.Output)
+	variables["expected"] = evaluator.Stringify(s.Expected)
+
+	req := &LLMRequest{Config: prompt.GetConfig()}
+	var generationInput any
+	if prompt.IsChat() {
+		if names := prompt.ChatPrompt.Placeholders(); len(names) > 0 {
+			return nil, fmt.Errorf("judge prompt has placeholders without messages: %s", strings.Join(names, ", "))
+		}
+		req.Messages = prompt.ChatPrompt.Compile(variables, nil)
+		generationInput = req.Messages
+	} else {
+		req.Prompt = prompt.TextPrompt.Compile(variables)
+		generationInput = req.Prompt
+	}
+
+	start := time.Now()
+	resp, err := j.caller.Call(ctx, req)
+	end := time.Now()
+	if err != nil {
+		return nil, fmt.Errorf("judge call failed: %w", err)
+	}
+
+	_, err = j.client.Generation((&model.Generation{
+		TraceID:             s.TraceID,
+		ParentObservationID: s.ObservationID,
+		Name:                "judge:" + j.PromptName,
+		StartTime:           &start,
+		EndTime:             &end,
+		Model:               resp.Model,
+		ModelParameters:     resp.ModelParameters,
+		Input:               generationInput,
+		Output:              resp.Content,
+		Usage:               resp.Usage,
+	}).WithPrompt(prompt), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	value, reasoning, err := parseJudgeResponse(resp.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	scoreName := j.ScoreName
+	if scoreName == "" {
+		scoreName = j.PromptName
+	}
+
+	return []model.Score{{
+		Name:     scoreName,
+		Value:    value,
+		DataType: model.ScoreDataTypeNumeric,
+		Comment:  reasoning,
+	}}, nil
+}
+
+// parseJudgeResponse extracts the score and reasoning from the JSON object in
+// the judge completion, ignoring any surrounding text such as code fences
+func parseJudgeResponse(content string) (float64, string, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return 0, "", fmt.Errorf("judge response contains no JSON object: %q", content)
+	}
+
+	var parsed struct {
+		Score     any    `json:"score"`
+		Reasoning string `json:"reasoning"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &parsed); err != nil {
+		return 0, "", fmt.Errorf("failed to parse judge response: %w", err)
+	}
+
+	switch v := parsed.Score.(type) {
+	case float64:
+		return v, parsed.Reasoning, nil
+	case bool:
+		if v {
+			return 1, parsed.Reasoning, nil
+		}
+		return 0, parsed.Reasoning, nil
+	case string:
+		value, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid judge score %q", v)
+		}
+		return value, parsed.Reasoning, nil
+	default:
+		return 0, "", fmt.Errorf("judge response has no score: %q", content)
+	}
+}
@@ -0,0 +1,127 @@
+package langfuse
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/optible/langfuse-go/evaluator"
+)
+
+type fakeLLMCaller struct {
+	req      *LLMRequest
+	response string
+}
+
+func (f *fakeLLMCaller) Call(_ context.Context, req *LLMRequest) (*LLMResponse, error) {
+	f.req = req
+	return &LLMResponse{Content: f.response, Model: "judge-model"}, nil
+}
+
+func TestLLMJudge_Evaluate(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/public/v2/prompts/") {
+			writeJSON(t, w, map[string]any{
+				"type":    "chat",
+				"name":    "correctness",
+				"version": 3,
+				"config":  map[string]any{"model": "gpt-4o"},
+				"prompt": []map[string]any{
+					{"role": "system", "content": "You grade answers for {{topic}}."},
+					{"role": "user", "content": "Q: {{input}}\nA: {{output}}\nExpected: {{expected}}"},
+				},
+			})
+			return
+		}
+		writeJSON(t, w, map[string]any{"successes": []any{}, "errors": []any{}})
+	})
+
+	caller := &fakeLLMCaller{response: "```json\n{\"score\": 0.75, \"reasoning\": \"Mostly right\"}\n```"}
+	judge := l.NewLLMJudge("correctness", caller)
+
+	scores, err := l.Evaluate(context.Background(), "trace-1", "", &evaluator.Sample{
+		Input:    "2+2?",
+		Output:   "4",
+		Expected: "4",
+		Metadata: map[string]any{"topic": "math"},
+	}, judge)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(caller.req.Messages) != 2 {
+		t.Fatalf("expected 2 compiled messages, got %d", len(caller.req.Messages))
+	}
+	if caller.req.Messages[0].Content != "You grade answers for math." {
+		t.Errorf("unexpected system message %q", caller.req.Messages[0].Content)
+	}
+	if caller.req.Messages[1].Content != "Q: 2+2?\nA: 4\nExpected: 4" {
+		t.Errorf("unexpected user message %q", caller.req.Messages[1].Content)
+	}
+
+	if len(scores) != 1 {
+		t.Fatalf("expected 1 score, got %d", len(scores))
+	}
+	score := scores[0]
+	if score.Name != "correctness" || score.Value != 0.75 || score.Comment != "Mostly right" || score.TraceID != "trace-1" {
+		t.Errorf("unexpected score %+v", score)
+	}
+}
+
+func TestLLMJudge_Placeholders(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{
+			"type":    "chat",
+			"name":    "correctness",
+			"version": 1,
+			"prompt": []map[string]any{
+				{"role": "system", "content": "Grade the answer."},
+				{"type": "placeholder", "name": "examples"},
+			},
+		})
+	})
+
+	caller := &fakeLLMCaller{response: `{"score": 1}`}
+	_, err := l.NewLLMJudge("correctness", caller).Evaluate(context.Background(), &evaluator.Sample{Output: "4"})
+	if err == nil || !strings.Contains(err.Error(), "examples") {
+		t.Errorf("expected placeholder error, got %v", err)
+	}
+	if caller.req != nil {
+		t.Error("expected the LLM not to be called")
+	}
+}
+
+func TestParseJudgeResponse(t *testing.T) {
+	tests := []struct {
+		content string
+		value   float64
+		wantErr bool
+	}{
+		{`{"score": 1, "reasoning": "ok"}`, 1, false},
+		{`Result: {"score": "0.5"}`, 0.5, false},
+		{`{"score": true}`, 1, false},
+		{`{"reasoning": "no score"}`, 0, true},
+		{`no json here`, 0, true},
+	}
+
+	for _, tt := range tests {
+		value, _, err := parseJudgeResponse(tt.content)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: expected error=%v, got %v", tt.content, tt.wantErr, err)
+			continue
+		}
+		if value != tt.value {
+			t.Errorf("%q: expected %v, got %v", tt.content, tt.value, value)
+		}
+	}
+}
+
+func TestLLMJudge_WithoutCaller(t *testing.T) {
+	l := New(context.Background())
+
+	_, err := l.NewLLMJudge("judge", nil).Evaluate(context.Background(), &evaluator.Sample{})
+	if err == nil {
+		t.Error("expected error without LLM caller")
+	}
+}
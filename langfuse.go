@@ -7,26 +7,52 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/optible/langfuse-go/internal/pkg/api"
-	"github.com/optible/langfuse-go/internal/pkg/cache"
 	"github.com/optible/langfuse-go/internal/pkg/observer"
+	"github.com/optible/langfuse-go/internal/pkg/singleflight"
 	"github.com/optible/langfuse-go/model"
 )
 
 const (
-	defaultFlushInterval   = 500 * time.Millisecond
-	defaultPromptCacheTTL  = 5 * time.Minute
+	defaultFlushInterval  = 500 * time.Millisecond
+	defaultPromptCacheTTL = 5 * time.Minute
+	defaultPromptLabel    = "production"
+	latestPromptLabel     = "latest"
+
+	defaultPromptFetchTimeout = 30 * time.Second
+
+	defaultPromptCacheMaxEntries      = 1000
+	defaultPromptCacheMaxStale        = 24 * time.Hour
+	defaultPromptCacheCleanupInterval = time.Minute
 )
 
 type Langfuse struct {
 	flushInterval  time.Duration
 	client         *api.Client
 	observer       *observer.Observer[model.IngestionEvent]
-	promptCache    *cache.Cache[*model.Prompt]
+	promptCache    PromptCache
 	promptCacheTTL time.Duration
+	promptFetches  singleflight.Group[*model.Prompt]
+	refreshErrors  sync.Map
+
+	// promptFetchStates tracks the cache keys being fetched, so that a fetch
+	// started before an invalidation does not cache its stale result
+	promptFetchMu     sync.Mutex
+	promptFetchStates map[string]*promptFetchState
+
+	// promptKeys maps prompt names to the keys they were cached under, and
+	// promptDependents to the keys of the composed prompts that embed them
+	promptKeys       promptKeyIndex
+	promptDependents promptKeyIndex
+
+	ingestionErrorHandler func(error)
+
+	fallbackMu      sync.RWMutex
+	fallbackPrompts map[string][]*model.Prompt
 }
 
 // GetPromptOptions contains options for fetching a prompt
@@ -41,6 +67,8 @@ type GetPromptOptions struct {
 
 	// FallbackPrompt is returned if the prompt cannot be fetched from the API.
 	// This provides guaranteed availability even during network issues.
+	// It takes precedence over the fallbacks registered with
+	// LoadFallbackPrompts. Returned fallbacks have IsFallback set.
 	FallbackPrompt *model.Prompt
 
 	// CacheTTL overrides the default cache TTL for this specific request.
@@ -53,26 +81,42 @@ type GetPromptOptions struct {
 
 	// ForceRefresh bypasses the cache and fetches directly from the API.
 	ForceRefresh bool
+
+	// Resolution controls how references to other prompts are resolved.
+	// Defaults to ResolveOnServer.
+	Resolution PromptResolution
+
+	// resolving lists the prompts whose references are being resolved
+	// locally, to detect circular references
+	resolving []string
 }
 
 func New(ctx context.Context) *Langfuse {
 	client := api.New()
 
+	promptCache := newMemoryPromptCache(defaultPromptCacheTTL)
+	go promptCache.cache.RunJanitor(ctx, defaultPromptCacheCleanupInterval)
+
 	l := &Langfuse{
 		flushInterval:  defaultFlushInterval,
 		client:         client,
-		promptCache:    cache.New[*model.Prompt](defaultPromptCacheTTL),
+		promptCache:    promptCache,
 		promptCacheTTL: defaultPromptCacheTTL,
-		observer: observer.NewObserver(
-			ctx,
-			func(ctx context.Context, events []model.IngestionEvent) {
-				err := ingest(ctx, client, events)
-				if err != nil {
-					fmt.Println(err)
-				}
-			},
-		),
 	}
+	l.observer = observer.NewObserver(
+		ctx,
+		func(ctx context.Context, events []model.IngestionEvent) {
+			err := ingest(ctx, client, events)
+			if err == nil {
+				return
+			}
+			if l.ingestionErrorHandler != nil {
+				l.ingestionErrorHandler(err)
+			} else {
+				fmt.Println(err)
+			}
+		},
+	)
 
 	return l
 }
@@ -82,17 +126,27 @@ func (l *Langfuse) WithFlushInterval(d time.Duration) *Langfuse {
 	return l
 }
 
+// WithIngestionErrorHandler sets the function called with the errors of
+// ingestion batches sent in the background: an error wrapping *APIError if
+// the batch was rejected, or an *IngestionError listing the rejected events
+// if the batch was accepted in part. By default, errors are printed.
+func (l *Langfuse) WithIngestionErrorHandler(handler func(error)) *Langfuse {
+	l.ingestionErrorHandler = handler
+	return l
+}
+
 // WithPromptCacheTTL sets the default cache TTL for prompts.
 // Default is 5 minutes.
 func (l *Langfuse) WithPromptCacheTTL(ttl time.Duration) *Langfuse {
 	l.promptCacheTTL = ttl
-	l.promptCache.SetTTL(ttl)
 	return l
 }
 
 // GetPrompt fetches a prompt by name.
 // By default, fetches the "production" labeled version.
-// Uses caching to minimize API calls.
+// Uses caching to minimize API calls: expired prompts are returned
+// immediately and refreshed in the background, and concurrent fetches of the
+// same prompt share a single API request.
 func (l *Langfuse) GetPrompt(ctx context.Context, name string, opts *GetPromptOptions) (*model.Prompt, error) {
 	if opts == nil {
 		opts = &GetPromptOptions{}
@@ -103,32 +157,38 @@ func (l *Langfuse) GetPrompt(ctx context.Context, name string, opts *GetPromptOp
 
 	// Check cache unless force refresh is requested
 	if !opts.ForceRefresh {
-		if prompt, found, expired := l.promptCache.Get(cacheKey); found {
-			if !expired {
-				return prompt, nil
+		// Cache errors are treated as misses
+		if cached, err := l.promptCache.Get(ctx, cacheKey); err == nil && cached != nil {
+			if cached.IsExpired() {
+				// Serve the stale value and refresh it in the background
+				l.refreshPrompt(ctx, name, opts, cacheKey)
+				return withPromptSource(cached.Prompt, model.PromptSourceStale, l.refreshError(cacheKey)), nil
 			}
-			// If expired but we have a cached value, try to refresh
-			// but return cached value if refresh fails
-			newPrompt, err := l.fetchPrompt(ctx, name, opts)
-			if err != nil {
-				// Return stale cached value on error
-				return prompt, nil
-			}
-			return newPrompt, nil
+			return withPromptSource(cached.Prompt, model.PromptSourceCached, nil), nil
 		}
 	}
 
 	// Fetch from API
-	prompt, err := l.fetchPrompt(ctx, name, opts)
+	prompt, err := l.fetchPromptOnce(ctx, name, opts, cacheKey)
 	if err != nil {
 		// If we have a fallback, return it
-		if opts.FallbackPrompt != nil {
-			return opts.FallbackPrompt, nil
+		if fallback := l.fallbackPrompt(name, opts); fallback != nil {
+			return withPromptSource(fallback, model.PromptSourceFallback, err), nil
 		}
 		return nil, err
 	}
 
-	return prompt, nil
+	return withPromptSource(prompt, model.PromptSourceFresh, nil), nil
+}
+
+// withPromptSource returns a copy of the prompt with its source, so that
+// prompts shared through the cache are not modified
+func withPromptSource(p *model.Prompt, source model.PromptSource, fetchErr error) *model.Prompt {
+	sourced := *p
+	sourced.Source = source
+	sourced.FetchError = fetchErr
+	sourced.IsFallback = source == model.PromptSourceFallback
+	return &sourced
 }
 
 // buildPromptCacheKey creates a unique cache key for a prompt request
@@ -139,11 +199,82 @@ func (l *Langfuse) buildPromptCacheKey(name string, opts *GetPromptOptions) stri
 	} else if opts.Label != nil {
 		key += ":l:" + *opts.Label
 	} else {
-		key += ":l:production"
+		key += ":l:" + defaultPromptLabel
+	}
+	if opts.Resolution == ResolveNone {
+		key += ":raw"
 	}
 	return key
 }
 
+// fetchPromptOnce fetches a prompt, sharing the result with concurrent
+// fetches of the same prompt. The shared fetch is not canceled with the
+// caller that started it; each caller stops waiting when its own context is
+// done or its FetchTimeout expires. Prompts referenced by a prompt being
+// resolved locally are fetched directly, so that circular references fail
+// instead of waiting for each other.
+func (l *Langfuse) fetchPromptOnce(ctx context.Context, name string, opts *GetPromptOptions, cacheKey string) (*model.Prompt, error) {
+	if len(opts.resolving) > 0 {
+		return l.fetchPrompt(ctx, name, opts)
+	}
+
+	if opts.FetchTimeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *opts.FetchTimeout)
+		defer cancel()
+	}
+
+	sharedCtx, sharedOpts := sharedFetch(ctx, opts)
+	ch := l.promptFetches.DoChan(cacheKey, func() (*model.Prompt, error) {
+		return l.fetchPrompt(sharedCtx, name, sharedOpts)
+	})
+
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to fetch prompt: %w", ctx.Err())
+	}
+}
+
+// sharedFetch returns the context and options of a fetch shared by several
+// callers or outliving its caller: it is not canceled with the caller's
+// context and always has a timeout
+func sharedFetch(ctx context.Context, opts *GetPromptOptions) (context.Context, *GetPromptOptions) {
+	shared := *opts
+	if shared.FetchTimeout == nil {
+		timeout := defaultPromptFetchTimeout
+		shared.FetchTimeout = &timeout
+	}
+	return context.WithoutCancel(ctx), &shared
+}
+
+// refreshPrompt refetches an expired prompt in a background goroutine,
+// unless a fetch of the prompt is already in flight. The refresh outlives
+// the request that triggered it; on failure the stale value stays cached.
+func (l *Langfuse) refreshPrompt(ctx context.Context, name string, opts *GetPromptOptions, cacheKey string) {
+	ctx, refreshOpts := sharedFetch(ctx, opts)
+
+	l.promptFetches.Go(cacheKey, func() (*model.Prompt, error) {
+		prompt, err := l.fetchPrompt(ctx, name, refreshOpts)
+		if err != nil {
+			l.refreshErrors.Store(cacheKey, err)
+		} else {
+			l.refreshErrors.Delete(cacheKey)
+		}
+		return prompt, err
+	})
+}
+
+// refreshError returns the error of the last failed background refresh of a
+// prompt, or nil if the last refresh succeeded
+func (l *Langfuse) refreshError(cacheKey string) error {
+	if err, ok := l.refreshErrors.Load(cacheKey); ok {
+		return err.(error)
+	}
+	return nil
+}
+
 // fetchPrompt fetches a prompt from the Langfuse API
 func (l *Langfuse) fetchPrompt(ctx context.Context, name string, opts *GetPromptOptions) (*model.Prompt, error) {
 	// Apply fetch timeout if specified
@@ -153,6 +284,10 @@ func (l *Langfuse) fetchPrompt(ctx context.Context, name string, opts *GetPrompt
 		defer cancel()
 	}
 
+	cacheKey := l.buildPromptCacheKey(name, opts)
+	generation := l.startPromptFetch(cacheKey)
+	defer l.endPromptFetch(cacheKey)
+
 	// Build URL path with query parameters
 	path := "/api/public/v2/prompts/" + url.PathEscape(name)
 
@@ -163,35 +298,41 @@ func (l *Langfuse) fetchPrompt(ctx context.Context, name string, opts *GetPrompt
 		params.Set("label", *opts.Label)
 	}
 	// Note: if neither version nor label is set, API defaults to "production"
+	if opts.Resolution != ResolveOnServer {
+		params.Set("resolve", "false")
+	}
 
 	if len(params) > 0 {
 		path = path + "?" + params.Encode()
 	}
 
 	// Make the API request
-	body, statusCode, err := l.client.DoGetRequest(ctx, path)
+	body, err := l.do(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch prompt: %w", err)
 	}
 
-	if statusCode >= http.StatusBadRequest {
-		return nil, fmt.Errorf("failed to fetch prompt: HTTP %d: %s", statusCode, string(body))
-	}
-
 	// Parse the response
 	prompt, err := parsePromptResponse(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse prompt response: %w", err)
 	}
 
+	if opts.Resolution == ResolveLocally {
+		prompt, err = l.resolvePromptReferences(ctx, prompt, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Cache the result
 	cacheTTL := l.promptCacheTTL
 	if opts.CacheTTL != nil {
 		cacheTTL = *opts.CacheTTL
 	}
 
-	cacheKey := l.buildPromptCacheKey(name, opts)
-	l.promptCache.SetWithTTL(cacheKey, prompt, cacheTTL)
+	// A failure to cache does not fail the fetch
+	l.cachePromptIfCurrent(ctx, cacheKey, generation, &CachedPrompt{Prompt: prompt, ExpiresAt: time.Now().Add(cacheTTL)})
 
 	return prompt, nil
 }
@@ -200,15 +341,16 @@ func (l *Langfuse) fetchPrompt(ctx context.Context, name string, opts *GetPrompt
 func parsePromptResponse(body []byte) (*model.Prompt, error) {
 	// First, parse the common fields to determine the type
 	var rawPrompt struct {
-		Type            string         `json:"type"`
-		Name            string         `json:"name"`
-		Version         int            `json:"version"`
-		Config          any            `json:"config"`
-		Labels          []string       `json:"labels"`
-		Tags            []string       `json:"tags"`
-		CommitMessage   *string        `json:"commitMessage,omitempty"`
-		ResolutionGraph map[string]any `json:"resolutionGraph,omitempty"`
-		Prompt          json.RawMessage `json:"prompt"`
+		ID              string                 `json:"id"`
+		Type            string                 `json:"type"`
+		Name            string                 `json:"name"`
+		Version         int                    `json:"version"`
+		Config          any                    `json:"config"`
+		Labels          []string               `json:"labels"`
+		Tags            []string               `json:"tags"`
+		CommitMessage   *string                `json:"commitMessage,omitempty"`
+		ResolutionGraph *model.ResolutionGraph `json:"resolutionGraph,omitempty"`
+		Prompt          json.RawMessage        `json:"prompt"`
 	}
 
 	if err := json.Unmarshal(body, &rawPrompt); err != nil {
@@ -225,6 +367,7 @@ func parsePromptResponse(body []byte) (*model.Prompt, error) {
 		}
 
 		result.TextPrompt = &model.TextPrompt{
+			ID:              rawPrompt.ID,
 			Name:            rawPrompt.Name,
 			Version:         rawPrompt.Version,
 			Config:          rawPrompt.Config,
@@ -243,6 +386,7 @@ func parsePromptResponse(body []byte) (*model.Prompt, error) {
 		}
 
 		result.ChatPrompt = &model.ChatPrompt{
+			ID:              rawPrompt.ID,
 			Name:            rawPrompt.Name,
 			Version:         rawPrompt.Version,
 			Config:          rawPrompt.Config,
@@ -263,7 +407,7 @@ func parsePromptResponse(body []byte) (*model.Prompt, error) {
 
 // ClearPromptCache clears all cached prompts
 func (l *Langfuse) ClearPromptCache() {
-	l.promptCache.Clear()
+	_ = l.promptCache.Clear(context.Background())
 }
 
 func ingest(ctx context.Context, client *api.Client, events []model.IngestionEvent) error {
@@ -272,7 +416,38 @@ func ingest(ctx context.Context, client *api.Client, events []model.IngestionEve
 	}
 
 	res := api.IngestionResponse{}
-	return client.Ingestion(ctx, &req, &res)
+	if err := client.Ingestion(ctx, &req, &res); err != nil {
+		return err
+	}
+
+	path, _ := req.Path()
+	if !res.IsSuccess() {
+		var body string
+		if res.RawBody != nil {
+			body = *res.RawBody
+		}
+		return newAPIError(http.MethodPost, path, res.Code, http.Header(res.Headers), []byte(body))
+	}
+
+	// Events can be rejected individually, e.g. in a 207 response
+	if len(res.Errors) == 0 {
+		return nil
+	}
+	ingestionErr := &IngestionError{}
+	for _, e := range res.Errors {
+		ingestionErr.Failures = append(ingestionErr.Failures, &IngestionEventError{
+			EventID: e.ID,
+			Err: &APIError{
+				StatusCode: e.Status,
+				Method:     http.MethodPost,
+				Path:       path,
+				Message:    e.Message,
+				Type:       e.Error,
+				RequestID:  http.Header(res.Headers).Get(requestIDHeader),
+			},
+		})
+	}
+	return ingestionErr
 }
 
 func (l *Langfuse) Trace(t *model.Trace) (*model.Trace, error) {
@@ -361,15 +536,10 @@ func (l *Langfuse) DeleteScore(ctx context.Context, scoreID string) error {
 	}
 
 	path := fmt.Sprintf("/api/public/scores/%s", scoreID)
-	body, statusCode, err := l.client.DoDeleteRequest(ctx, path)
-	if err != nil {
+	if err := l.deleteResource(ctx, path); err != nil {
 		return fmt.Errorf("failed to delete score: %w", err)
 	}
 
-	if statusCode >= http.StatusBadRequest {
-		return fmt.Errorf("failed to delete score: HTTP %d: %s", statusCode, string(body))
-	}
-
 	return nil
 }
 
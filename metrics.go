@@ -0,0 +1,86 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// DailyMetricsFilter contains the filters for fetching daily metrics.
+// Empty fields are not sent to the API.
+type DailyMetricsFilter struct {
+	TraceName   string
+	UserID      string
+	Tags        []string
+	Environment []string
+
+	// FromTimestamp only includes traces created at or after this time.
+	FromTimestamp *time.Time
+	// ToTimestamp only includes traces created before this time.
+	ToTimestamp *time.Time
+
+	// Page is the 1-based page to fetch. Defaults to the first page.
+	Page int
+	// Limit is the number of days per page. Defaults to the API's default.
+	Limit int
+}
+
+func (f *DailyMetricsFilter) params() url.Values {
+	params := url.Values{}
+	setIntParam(params, "page", f.Page)
+	setIntParam(params, "limit", f.Limit)
+	setParam(params, "traceName", f.TraceName)
+	setParam(params, "userId", f.UserID)
+	for _, tag := range f.Tags {
+		params.Add("tags", tag)
+	}
+	for _, env := range f.Environment {
+		params.Add("environment", env)
+	}
+	setTimeParam(params, "fromTimestamp", f.FromTimestamp)
+	setTimeParam(params, "toTimestamp", f.ToTimestamp)
+	return params
+}
+
+// GetDailyMetrics fetches the daily trace counts, cost and token usage per model.
+func (l *Langfuse) GetDailyMetrics(ctx context.Context, filter *DailyMetricsFilter) (*model.DailyMetricsList, error) {
+	if filter == nil {
+		filter = &DailyMetricsFilter{}
+	}
+
+	list := &model.DailyMetricsList{}
+	err := l.getJSON(ctx, buildPath("/api/public/metrics/daily", filter.params()), list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily metrics: %w", err)
+	}
+
+	return list, nil
+}
+
+// QueryMetrics runs a query against the metrics API, e.g. to get cost and
+// token usage grouped by model, user, tag or environment.
+func (l *Langfuse) QueryMetrics(ctx context.Context, query *model.MetricsQuery) (*model.MetricsResult, error) {
+	if query == nil {
+		return nil, fmt.Errorf("metrics query is required")
+	}
+
+	encoded, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode metrics query: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("query", string(encoded))
+
+	result := &model.MetricsResult{}
+	err = l.getJSON(ctx, buildPath("/api/public/metrics", params), result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metrics: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+func TestGetDailyMetrics(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/metrics/daily" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if tags := r.URL.Query()["tags"]; len(tags) != 2 {
+			t.Errorf("expected 2 tags, got %v", tags)
+		}
+		writeJSON(t, w, map[string]any{
+			"data": []map[string]any{{
+				"date":        "2024-01-01",
+				"countTraces": 3,
+				"totalCost":   1.5,
+				"usage": []map[string]any{
+					{"model": "gpt-4o", "inputUsage": 100, "outputUsage": 50, "totalUsage": 150, "totalCost": 1.5},
+				},
+			}},
+			"meta": map[string]any{"page": 1, "limit": 50, "totalItems": 1, "totalPages": 1},
+		})
+	})
+
+	list, err := l.GetDailyMetrics(context.Background(), &DailyMetricsFilter{Tags: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(list.Data) != 1 || list.Data[0].Usage[0].Model != "gpt-4o" || list.Data[0].TotalCost != 1.5 {
+		t.Errorf("unexpected daily metrics: %+v", list.Data)
+	}
+}
+
+func TestQueryMetrics(t *testing.T) {
+	from := mustParseTime(t, "2024-01-01T00:00:00Z")
+	to := mustParseTime(t, "2024-02-01T00:00:00Z")
+
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var query model.MetricsQuery
+		if err := json.Unmarshal([]byte(r.URL.Query().Get("query")), &query); err != nil {
+			t.Fatalf("failed to decode query: %v", err)
+		}
+		if query.View != model.MetricsViewObservations {
+			t.Errorf("unexpected view %q", query.View)
+		}
+		if len(query.Dimensions) != 1 || query.Dimensions[0].Field != model.MetricsDimensionModel {
+			t.Errorf("unexpected dimensions %+v", query.Dimensions)
+		}
+		if len(query.Metrics) != 2 || query.Metrics[0].Aggregation != model.MetricsAggregationSum {
+			t.Errorf("unexpected metrics %+v", query.Metrics)
+		}
+		if len(query.Filters) != 1 || query.Filters[0].Type != "arrayOptions" {
+			t.Errorf("unexpected filters %+v", query.Filters)
+		}
+		if !query.FromTimestamp.Equal(from) || !query.ToTimestamp.Equal(to) {
+			t.Errorf("unexpected time range %v - %v", query.FromTimestamp, query.ToTimestamp)
+		}
+		writeJSON(t, w, map[string]any{
+			"data": []map[string]any{
+				{"providedModelName": "gpt-4o", "sum_totalCost": 12.5, "sum_totalTokens": 1000},
+			},
+		})
+	})
+
+	query := model.NewMetricsQuery(model.MetricsViewObservations, from, to).
+		GroupBy(model.MetricsDimensionModel).
+		Metric(model.MetricsMeasureTotalCost, model.MetricsAggregationSum).
+		Metric(model.MetricsMeasureTotalTokens, model.MetricsAggregationSum).
+		WhereTags("production")
+
+	result, err := l.QueryMetrics(context.Background(), query)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(result.Data) != 1 || result.Data[0]["sum_totalCost"] != 12.5 {
+		t.Errorf("unexpected result: %+v", result.Data)
+	}
+}
+
+func TestQueryMetrics_WithNilQuery(t *testing.T) {
+	l := New(context.Background())
+
+	if _, err := l.QueryMetrics(context.Background(), nil); err == nil {
+		t.Error("expected error for nil query")
+	}
+}
@@ -0,0 +1,254 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/optible/langfuse-go/internal/pkg/mustache"
+)
+
+// ChatMessageType distinguishes regular chat messages from placeholders
+type ChatMessageType string
+
+const (
+	ChatMessageTypeMessage     ChatMessageType = "chatmessage"
+	ChatMessageTypePlaceholder ChatMessageType = "placeholder"
+)
+
+// ChatMessage represents a single message in a chat prompt or generation.
+// The content is either the text in Content or, for multimodal messages, the
+// list of parts in Parts. A placeholder entry has the type
+// ChatMessageTypePlaceholder and a name, and is replaced by a list of
+// messages such as the chat history when compiling the prompt.
+type ChatMessage struct {
+	Type    ChatMessageType
+	Role    string
+	Content string
+	Parts   []ContentPart
+
+	// Name is the name of the participant, or of the placeholder
+	Name string
+
+	// ToolCalls are the tools called by an assistant message
+	ToolCalls []ToolCall
+
+	// ToolCallID is the ID of the call a tool message responds to
+	ToolCallID string
+}
+
+// ContentPartType is the type of a part of a multimodal message
+type ContentPartType string
+
+const (
+	ContentPartTypeText     ContentPartType = "text"
+	ContentPartTypeImageURL ContentPartType = "image_url"
+)
+
+// ContentPart is a part of a multimodal chat message. Fields of part types
+// not modelled here are kept in Extra, so that parts survive a round trip.
+type ContentPart struct {
+	Type     ContentPartType
+	Text     string
+	ImageURL *ImageURL
+	Extra    map[string]json.RawMessage
+}
+
+// ImageURL is the image of an image_url content part
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ToolCall is a call of a tool requested by the model
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction is the function called by a tool call, with its arguments
+// encoded as JSON
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// IsPlaceholder returns true if the message is a placeholder
+func (m ChatMessage) IsPlaceholder() bool {
+	return m.Type == ChatMessageTypePlaceholder
+}
+
+// chatMessageJSON is the wire format of a chat message
+type chatMessageJSON struct {
+	Type       ChatMessageType `json:"type,omitempty"`
+	Role       string          `json:"role"`
+	Content    json.RawMessage `json:"content"`
+	Name       string          `json:"name,omitempty"`
+	ToolCalls  []ToolCall      `json:"tool_calls,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+}
+
+// MarshalJSON encodes the content as a string or a list of parts, and
+// placeholders with their type and name only. The empty content of a message
+// with tool calls is encoded as null.
+func (m ChatMessage) MarshalJSON() ([]byte, error) {
+	if m.IsPlaceholder() {
+		return json.Marshal(struct {
+			Type ChatMessageType `json:"type"`
+			Name string          `json:"name"`
+		}{m.Type, m.Name})
+	}
+
+	var content any = m.Content
+	switch {
+	case m.Parts != nil:
+		content = m.Parts
+	case m.Content == "" && len(m.ToolCalls) > 0:
+		content = nil
+	}
+
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(chatMessageJSON{
+		Type:       m.Type,
+		Role:       m.Role,
+		Content:    raw,
+		Name:       m.Name,
+		ToolCalls:  m.ToolCalls,
+		ToolCallID: m.ToolCallID,
+	})
+}
+
+// UnmarshalJSON decodes a message with string, list or null content
+func (m *ChatMessage) UnmarshalJSON(data []byte) error {
+	var raw chatMessageJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*m = ChatMessage{
+		Type:       raw.Type,
+		Role:       raw.Role,
+		Name:       raw.Name,
+		ToolCalls:  raw.ToolCalls,
+		ToolCallID: raw.ToolCallID,
+	}
+
+	content := bytes.TrimSpace(raw.Content)
+	switch {
+	case len(content) == 0 || bytes.Equal(content, []byte("null")):
+	case content[0] == '"':
+		return json.Unmarshal(content, &m.Content)
+	case content[0] == '[':
+		return json.Unmarshal(content, &m.Parts)
+	default:
+		return fmt.Errorf("unsupported chat message content %s", content)
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the part with its extra fields
+func (p ContentPart) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]any, len(p.Extra)+3)
+	for key, value := range p.Extra {
+		fields[key] = value
+	}
+
+	fields["type"] = p.Type
+	if p.Type == ContentPartTypeText || p.Text != "" {
+		fields["text"] = p.Text
+	}
+	if p.ImageURL != nil {
+		fields["image_url"] = p.ImageURL
+	}
+
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes the part, keeping unknown fields in Extra
+func (p *ContentPart) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	*p = ContentPart{}
+	known := map[string]any{
+		"type":      &p.Type,
+		"text":      &p.Text,
+		"image_url": &p.ImageURL,
+	}
+	for key, value := range fields {
+		target, ok := known[key]
+		if !ok {
+			if p.Extra == nil {
+				p.Extra = map[string]json.RawMessage{}
+			}
+			p.Extra[key] = value
+			continue
+		}
+		if err := json.Unmarshal(value, target); err != nil {
+			return fmt.Errorf("failed to decode content part %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// templates returns the texts of the message that are compiled as templates:
+// the content, or the text parts of a multimodal message
+func (m ChatMessage) templates() []string {
+	if m.Parts == nil {
+		return []string{m.Content}
+	}
+
+	var texts []string
+	for _, part := range m.Parts {
+		if part.Type == ContentPartTypeText {
+			texts = append(texts, part.Text)
+		}
+	}
+	return texts
+}
+
+// compileMessage renders the content or the text parts of a message. Unless
+// strict is set, malformed tags are kept as literal text. Otherwise texts
+// that are not valid templates are kept as is and the first error is
+// returned along with the compiled message.
+func compileMessage(msg ChatMessage, variables map[string]any, strict bool) (ChatMessage, []*mustache.Result, error) {
+	var results []*mustache.Result
+	var firstErr error
+
+	compile := func(text string) string {
+		result, err := compileTemplate(text, variables, strict)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return text
+		}
+		results = append(results, result)
+		return result.Output
+	}
+
+	if msg.Parts == nil {
+		msg.Content = compile(msg.Content)
+		return msg, results, firstErr
+	}
+
+	parts := make([]ContentPart, len(msg.Parts))
+	for i, part := range msg.Parts {
+		if part.Type == ContentPartTypeText {
+			part.Text = compile(part.Text)
+		}
+		parts[i] = part
+	}
+	msg.Parts = parts
+
+	return msg, results, firstErr
+}
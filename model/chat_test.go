@@ -0,0 +1,87 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestChatMessage_JSONRoundTrip(t *testing.T) {
+	messages := []string{
+		`{"role":"user","content":"Hi"}`,
+		`{"type":"chatmessage","role":"system","content":"Be brief."}`,
+		`{"type":"placeholder","name":"history"}`,
+		`{"role":"user","content":[{"text":"What is in {{what}}?","type":"text"},{"image_url":{"url":"https://example.com/a.png","detail":"low"},"type":"image_url"}],"name":"alice"}`,
+		`{"role":"user","content":[{"input_audio":{"data":"AAA","format":"wav"},"type":"input_audio"}]}`,
+		`{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"lookup","arguments":"{\"q\":\"x\"}"}}]}`,
+		`{"role":"tool","content":"42","tool_call_id":"call_1"}`,
+	}
+
+	for _, data := range messages {
+		var msg ChatMessage
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			t.Fatalf("failed to unmarshal %s: %v", data, err)
+		}
+
+		encoded, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatalf("failed to marshal %s: %v", data, err)
+		}
+		if string(encoded) != data {
+			t.Errorf("expected %s, got %s", data, encoded)
+		}
+	}
+}
+
+func TestChatMessage_UnmarshalFields(t *testing.T) {
+	var msg ChatMessage
+	data := `{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"lookup","arguments":"{}"}}]}`
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if msg.Content != "" || msg.Parts != nil || len(msg.ToolCalls) != 1 || msg.ToolCalls[0].Function.Name != "lookup" {
+		t.Errorf("unexpected message %+v", msg)
+	}
+
+	if err := json.Unmarshal([]byte(`{"role":"user","content":1}`), &msg); err == nil {
+		t.Error("expected error for numeric content")
+	}
+}
+
+func TestChatPrompt_CompileContentParts(t *testing.T) {
+	image := ContentPart{Type: ContentPartTypeImageURL, ImageURL: &ImageURL{URL: "https://example.com/{{image}}.png"}}
+	prompt := &ChatPrompt{
+		Prompt: []ChatMessage{
+			{Role: "user", Name: "alice", Parts: []ContentPart{
+				{Type: ContentPartTypeText, Text: "Describe {{ subject }}."},
+				image,
+			}},
+			{Role: "tool", Content: "{{result}}", ToolCallID: "call_1"},
+		},
+	}
+
+	if vars := prompt.Variables(); !reflect.DeepEqual(vars, []string{"subject", "result"}) {
+		t.Errorf("unexpected variables %v", vars)
+	}
+
+	result, err := prompt.CompileStrict(map[string]any{"subject": "the cat", "result": 42}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []ChatMessage{
+		{Role: "user", Name: "alice", Parts: []ContentPart{
+			{Type: ContentPartTypeText, Text: "Describe the cat."},
+			image,
+		}},
+		{Role: "tool", Content: "42", ToolCallID: "call_1"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+
+	// The prompt itself is not modified
+	if prompt.Prompt[0].Parts[0].Text != "Describe {{ subject }}." {
+		t.Errorf("prompt was modified: %q", prompt.Prompt[0].Parts[0].Text)
+	}
+}
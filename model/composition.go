@@ -0,0 +1,189 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// promptReferencePattern matches prompt reference tags such as
+// @@@langfusePrompt:name=greeting|label=production@@@
+var promptReferencePattern = regexp.MustCompile(`@@@langfusePrompt:(.*?)@@@`)
+
+// PromptReference is a reference to another prompt embedded in a prompt,
+// which is replaced by the text of the referenced prompt when resolving.
+// Either the version or the label identifies the referenced prompt version.
+type PromptReference struct {
+	// Tag is the reference as written in the prompt
+	Tag string
+
+	Name    string
+	Version *int
+	Label   *string
+}
+
+// ParsePromptReferences returns the prompt references in text, in order of
+// appearance and without duplicates
+func ParsePromptReferences(text string) ([]PromptReference, error) {
+	var refs []PromptReference
+	seen := map[string]bool{}
+
+	for _, match := range promptReferencePattern.FindAllStringSubmatch(text, -1) {
+		if seen[match[0]] {
+			continue
+		}
+		seen[match[0]] = true
+
+		ref, err := parsePromptReference(match[0], match[1])
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
+
+func parsePromptReference(tag, params string) (PromptReference, error) {
+	ref := PromptReference{Tag: tag}
+
+	for _, param := range strings.Split(params, "|") {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return ref, fmt.Errorf("invalid prompt reference %s", tag)
+		}
+
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "name":
+			ref.Name = value
+		case "version":
+			version, err := strconv.Atoi(value)
+			if err != nil {
+				return ref, fmt.Errorf("invalid version in prompt reference %s", tag)
+			}
+			ref.Version = &version
+		case "label":
+			ref.Label = &value
+		}
+	}
+
+	if ref.Name == "" {
+		return ref, fmt.Errorf("prompt reference %s has no name", tag)
+	}
+	if ref.Version == nil && ref.Label == nil {
+		return ref, fmt.Errorf("prompt reference %s has neither version nor label", tag)
+	}
+
+	return ref, nil
+}
+
+// References returns the prompt references in the prompt, or in the content
+// and text parts of its messages
+func (p *Prompt) References() ([]PromptReference, error) {
+	var texts []string
+	switch {
+	case p.TextPrompt != nil:
+		texts = []string{p.TextPrompt.Prompt}
+	case p.ChatPrompt != nil:
+		for _, msg := range p.ChatPrompt.Prompt {
+			if !msg.IsPlaceholder() {
+				texts = append(texts, msg.templates()...)
+			}
+		}
+	}
+
+	refs, err := ParsePromptReferences(strings.Join(texts, "\n"))
+	if err != nil {
+		return nil, fmt.Errorf("prompt %s: %w", p.GetName(), err)
+	}
+	return refs, nil
+}
+
+// ReplaceReferences returns a copy of the prompt with the reference tags that
+// are keys of replacement replaced by their values
+func (p *Prompt) ReplaceReferences(replacement map[string]string) *Prompt {
+	var pairs []string
+	for tag, text := range replacement {
+		pairs = append(pairs, tag, text)
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	switch {
+	case p.TextPrompt != nil:
+		text := *p.TextPrompt
+		text.Prompt = replacer.Replace(text.Prompt)
+		return &Prompt{TextPrompt: &text}
+
+	case p.ChatPrompt != nil:
+		chat := *p.ChatPrompt
+		chat.Prompt = make([]ChatMessage, len(p.ChatPrompt.Prompt))
+		for i, msg := range p.ChatPrompt.Prompt {
+			if msg.Parts == nil {
+				msg.Content = replacer.Replace(msg.Content)
+			} else {
+				parts := make([]ContentPart, len(msg.Parts))
+				for j, part := range msg.Parts {
+					if part.Type == ContentPartTypeText {
+						part.Text = replacer.Replace(part.Text)
+					}
+					parts[j] = part
+				}
+				msg.Parts = parts
+			}
+			chat.Prompt[i] = msg
+		}
+		return &Prompt{ChatPrompt: &chat}
+	}
+
+	return p
+}
+
+// ResolutionGraph describes the prompts a composed prompt was resolved from
+type ResolutionGraph struct {
+	Root PromptNode `json:"root"`
+
+	// Dependencies maps the ID of a prompt to the prompts it references
+	Dependencies map[string][]PromptNode `json:"dependencies"`
+}
+
+// PromptNode identifies a prompt version in a resolution graph
+type PromptNode struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+}
+
+// GetID returns the prompt ID
+func (p *Prompt) GetID() string {
+	if p.TextPrompt != nil {
+		return p.TextPrompt.ID
+	}
+	if p.ChatPrompt != nil {
+		return p.ChatPrompt.ID
+	}
+	return ""
+}
+
+// GetResolutionGraph returns the resolution graph of a composed prompt, or
+// nil if the prompt does not reference other prompts
+func (p *Prompt) GetResolutionGraph() *ResolutionGraph {
+	if p.TextPrompt != nil {
+		return p.TextPrompt.ResolutionGraph
+	}
+	if p.ChatPrompt != nil {
+		return p.ChatPrompt.ResolutionGraph
+	}
+	return nil
+}
+
+// SetResolutionGraph sets the resolution graph of the prompt
+func (p *Prompt) SetResolutionGraph(graph *ResolutionGraph) {
+	if p.TextPrompt != nil {
+		p.TextPrompt.ResolutionGraph = graph
+	}
+	if p.ChatPrompt != nil {
+		p.ChatPrompt.ResolutionGraph = graph
+	}
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestParsePromptReferences(t *testing.T) {
+	refs, err := ParsePromptReferences("A @@@langfusePrompt:name=base|version=2@@@ B @@@langfusePrompt:name=tone|label=staging@@@ @@@langfusePrompt:name=base|version=2@@@")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 references, got %d", len(refs))
+	}
+	if refs[0].Name != "base" || refs[0].Version == nil || *refs[0].Version != 2 || refs[0].Tag != "@@@langfusePrompt:name=base|version=2@@@" {
+		t.Errorf("unexpected reference %+v", refs[0])
+	}
+	if refs[1].Name != "tone" || refs[1].Label == nil || *refs[1].Label != "staging" {
+		t.Errorf("unexpected reference %+v", refs[1])
+	}
+}
+
+func TestParsePromptReferences_Invalid(t *testing.T) {
+	for _, text := range []string{
+		"@@@langfusePrompt:label=production@@@",
+		"@@@langfusePrompt:name=base@@@",
+		"@@@langfusePrompt:name=base|version=latest@@@",
+		"@@@langfusePrompt:name@@@",
+	} {
+		if _, err := ParsePromptReferences(text); err == nil {
+			t.Errorf("expected error for %q", text)
+		}
+	}
+}
+
+func TestPrompt_ReplaceReferences(t *testing.T) {
+	tag := "@@@langfusePrompt:name=base|label=production@@@"
+	prompt := &Prompt{ChatPrompt: &ChatPrompt{Prompt: []ChatMessage{
+		{Role: "system", Content: tag + " Be brief."},
+		{Role: "user", Parts: []ContentPart{{Type: ContentPartTypeText, Text: tag}}},
+	}}}
+
+	refs, err := prompt.References()
+	if err != nil || len(refs) != 1 {
+		t.Fatalf("expected 1 reference, got %v, %v", refs, err)
+	}
+
+	resolved := prompt.ReplaceReferences(map[string]string{tag: "You are helpful."})
+	if resolved.ChatPrompt.Prompt[0].Content != "You are helpful. Be brief." {
+		t.Errorf("unexpected content %q", resolved.ChatPrompt.Prompt[0].Content)
+	}
+	if resolved.ChatPrompt.Prompt[1].Parts[0].Text != "You are helpful." {
+		t.Errorf("unexpected text part %q", resolved.ChatPrompt.Prompt[1].Parts[0].Text)
+	}
+	if prompt.ChatPrompt.Prompt[0].Content != tag+" Be brief." {
+		t.Error("expected original prompt to be unchanged")
+	}
+}
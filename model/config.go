@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ModelConfig is the conventional shape of a prompt config used to
+// parameterize the model call, following the OpenAI request fields
+type ModelConfig struct {
+	Model          string          `json:"model,omitempty"`
+	Temperature    *float64        `json:"temperature,omitempty"`
+	MaxTokens      *int            `json:"max_tokens,omitempty"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	Tools          []Tool          `json:"tools,omitempty"`
+}
+
+// ResponseFormat constrains the format of the model output, e.g. to
+// {"type": "json_schema"} with a JSON schema
+type ResponseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+// JSONSchema is a named JSON schema of a structured output
+type JSONSchema struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Schema      map[string]any `json:"schema,omitempty"`
+	Strict      *bool          `json:"strict,omitempty"`
+}
+
+// Tool is a tool the model may call
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction describes a function tool and its JSON schema parameters
+type ToolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+	Strict      *bool          `json:"strict,omitempty"`
+}
+
+// DecodeConfig decodes the prompt config into a value of type T, such as
+// ModelConfig or an application specific struct. A prompt without config
+// decodes to the zero value.
+func DecodeConfig[T any](p *Prompt) (T, error) {
+	var config T
+	if p == nil {
+		return config, fmt.Errorf("prompt is required")
+	}
+
+	raw := p.GetConfig()
+	if raw == nil {
+		return config, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return config, fmt.Errorf("failed to encode prompt config: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to decode prompt config: %w", err)
+	}
+
+	return config, nil
+}
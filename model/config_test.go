@@ -0,0 +1,71 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeConfig_ModelConfig(t *testing.T) {
+	var raw any
+	err := json.Unmarshal([]byte(`{
+		"model": "gpt-4o",
+		"temperature": 0.2,
+		"max_tokens": 256,
+		"response_format": {
+			"type": "json_schema",
+			"json_schema": {"name": "answer", "schema": {"type": "object"}, "strict": true}
+		},
+		"tools": [{"type": "function", "function": {"name": "lookup", "parameters": {"type": "object"}}}],
+		"custom": "ignored"
+	}`), &raw)
+	if err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	config, err := DecodeConfig[ModelConfig](&Prompt{ChatPrompt: &ChatPrompt{Config: raw}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if config.Model != "gpt-4o" || *config.Temperature != 0.2 || *config.MaxTokens != 256 {
+		t.Errorf("unexpected config %+v", config)
+	}
+	if config.ResponseFormat == nil || config.ResponseFormat.JSONSchema.Name != "answer" || !*config.ResponseFormat.JSONSchema.Strict {
+		t.Errorf("unexpected response format %+v", config.ResponseFormat)
+	}
+	if len(config.Tools) != 1 || config.Tools[0].Function.Name != "lookup" {
+		t.Errorf("unexpected tools %+v", config.Tools)
+	}
+}
+
+func TestDecodeConfig_CustomType(t *testing.T) {
+	type retrievalConfig struct {
+		TopK int `json:"top_k"`
+	}
+
+	config, err := DecodeConfig[retrievalConfig](&Prompt{TextPrompt: &TextPrompt{Config: map[string]any{"top_k": 5}}})
+	if err != nil || config.TopK != 5 {
+		t.Errorf("unexpected config %+v, error %v", config, err)
+	}
+
+	_, err = DecodeConfig[retrievalConfig](&Prompt{TextPrompt: &TextPrompt{Config: map[string]any{"top_k": "five"}}})
+	if err == nil {
+		t.Error("expected error for mismatched config")
+	}
+}
+
+func TestDecodeConfig_NoConfig(t *testing.T) {
+	config, err := DecodeConfig[ModelConfig](&Prompt{TextPrompt: &TextPrompt{}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if config.Model != "" || config.Temperature != nil {
+		t.Errorf("expected zero config, got %+v", config)
+	}
+}
+
+func TestDecodeConfig_NilPrompt(t *testing.T) {
+	if _, err := DecodeConfig[ModelConfig](nil); err == nil {
+		t.Error("expected error for nil prompt")
+	}
+}
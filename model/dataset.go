@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// Dataset is a named collection of dataset items used for evaluations and experiments
+type Dataset struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Metadata    any        `json:"metadata,omitempty"`
+	ProjectID   string     `json:"projectId,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
+
+// DatasetList is a single page of datasets
+type DatasetList struct {
+	Data []Dataset      `json:"data"`
+	Meta PaginationMeta `json:"meta"`
+}
+
+// DatasetItemStatus represents whether a dataset item is used in experiments
+type DatasetItemStatus string
+
+const (
+	DatasetItemStatusActive   DatasetItemStatus = "ACTIVE"
+	DatasetItemStatusArchived DatasetItemStatus = "ARCHIVED"
+)
+
+// DatasetItem is a single input/expected output pair of a dataset, optionally
+// linked to the trace or observation it was created from
+type DatasetItem struct {
+	ID                  string            `json:"id,omitempty"`
+	DatasetName         string            `json:"datasetName,omitempty"`
+	DatasetID           string            `json:"datasetId,omitempty"`
+	Status              DatasetItemStatus `json:"status,omitempty"`
+	Input               any               `json:"input,omitempty"`
+	ExpectedOutput      any               `json:"expectedOutput,omitempty"`
+	Metadata            any               `json:"metadata,omitempty"`
+	SourceTraceID       string            `json:"sourceTraceId,omitempty"`
+	SourceObservationID string            `json:"sourceObservationId,omitempty"`
+	CreatedAt           *time.Time        `json:"createdAt,omitempty"`
+	UpdatedAt           *time.Time        `json:"updatedAt,omitempty"`
+}
+
+// DatasetItemList is a single page of dataset items
+type DatasetItemList struct {
+	Data []DatasetItem  `json:"data"`
+	Meta PaginationMeta `json:"meta"`
+}
+
+// DatasetRunItem links the trace (and optionally the observation) produced
+// for a dataset item to a named dataset run
+type DatasetRunItem struct {
+	ID             string     `json:"id,omitempty"`
+	RunName        string     `json:"runName"`
+	RunDescription string     `json:"runDescription,omitempty"`
+	Metadata       any        `json:"metadata,omitempty"`
+	DatasetItemID  string     `json:"datasetItemId"`
+	TraceID        string     `json:"traceId,omitempty"`
+	ObservationID  string     `json:"observationId,omitempty"`
+	DatasetRunID   string     `json:"datasetRunId,omitempty"`
+	DatasetRunName string     `json:"datasetRunName,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,195 @@
+package model
+
+import "time"
+
+// DailyMetrics contains the aggregated usage of a single day
+type DailyMetrics struct {
+	Date              string         `json:"date"`
+	CountTraces       int            `json:"countTraces"`
+	CountObservations int            `json:"countObservations"`
+	TotalCost         float64        `json:"totalCost"`
+	Usage             []UsageByModel `json:"usage"`
+}
+
+// UsageByModel contains the aggregated usage of a single model
+type UsageByModel struct {
+	Model             string  `json:"model"`
+	InputUsage        int     `json:"inputUsage"`
+	OutputUsage       int     `json:"outputUsage"`
+	TotalUsage        int     `json:"totalUsage"`
+	CountTraces       int     `json:"countTraces"`
+	CountObservations int     `json:"countObservations"`
+	TotalCost         float64 `json:"totalCost"`
+}
+
+// DailyMetricsList is a single page of daily metrics
+type DailyMetricsList struct {
+	Data []DailyMetrics `json:"data"`
+	Meta PaginationMeta `json:"meta"`
+}
+
+// MetricsView is the data set a metrics query runs against
+type MetricsView string
+
+const (
+	MetricsViewTraces            MetricsView = "traces"
+	MetricsViewObservations      MetricsView = "observations"
+	MetricsViewScoresNumeric     MetricsView = "scores-numeric"
+	MetricsViewScoresCategorical MetricsView = "scores-categorical"
+)
+
+// Commonly used dimensions to group metrics by
+const (
+	MetricsDimensionModel       = "providedModelName"
+	MetricsDimensionUser        = "userId"
+	MetricsDimensionTags        = "tags"
+	MetricsDimensionEnvironment = "environment"
+	MetricsDimensionName        = "name"
+)
+
+// Commonly used measures
+const (
+	MetricsMeasureCount        = "count"
+	MetricsMeasureTotalCost    = "totalCost"
+	MetricsMeasureTotalTokens  = "totalTokens"
+	MetricsMeasureInputTokens  = "inputTokens"
+	MetricsMeasureOutputTokens = "outputTokens"
+	MetricsMeasureLatency      = "latency"
+)
+
+// MetricsAggregation is the aggregation applied to a measure
+type MetricsAggregation string
+
+const (
+	MetricsAggregationSum   MetricsAggregation = "sum"
+	MetricsAggregationAvg   MetricsAggregation = "avg"
+	MetricsAggregationCount MetricsAggregation = "count"
+	MetricsAggregationMax   MetricsAggregation = "max"
+	MetricsAggregationMin   MetricsAggregation = "min"
+	MetricsAggregationP50   MetricsAggregation = "p50"
+	MetricsAggregationP95   MetricsAggregation = "p95"
+	MetricsAggregationP99   MetricsAggregation = "p99"
+)
+
+// MetricsGranularity is the bucket size of the time dimension
+type MetricsGranularity string
+
+const (
+	MetricsGranularityMinute MetricsGranularity = "minute"
+	MetricsGranularityHour   MetricsGranularity = "hour"
+	MetricsGranularityDay    MetricsGranularity = "day"
+	MetricsGranularityWeek   MetricsGranularity = "week"
+	MetricsGranularityMonth  MetricsGranularity = "month"
+	MetricsGranularityAuto   MetricsGranularity = "auto"
+)
+
+type MetricsDimension struct {
+	Field string `json:"field"`
+}
+
+type MetricsMetric struct {
+	Measure     string             `json:"measure"`
+	Aggregation MetricsAggregation `json:"aggregation"`
+}
+
+type MetricsFilter struct {
+	Column   string `json:"column"`
+	Operator string `json:"operator"`
+	Value    any    `json:"value"`
+	Type     string `json:"type"`
+	Key      string `json:"key,omitempty"`
+}
+
+type MetricsTimeDimension struct {
+	Granularity MetricsGranularity `json:"granularity"`
+}
+
+type MetricsOrderBy struct {
+	Field     string `json:"field"`
+	Direction string `json:"direction"`
+}
+
+// MetricsQuery is a query for the metrics API. Build it with NewMetricsQuery
+// and the chainable methods instead of writing the JSON by hand:
+//
+//	q := model.NewMetricsQuery(model.MetricsViewObservations, from, to).
+//		GroupBy(model.MetricsDimensionModel).
+//		Metric(model.MetricsMeasureTotalCost, model.MetricsAggregationSum)
+type MetricsQuery struct {
+	View          MetricsView           `json:"view"`
+	Dimensions    []MetricsDimension    `json:"dimensions"`
+	Metrics       []MetricsMetric       `json:"metrics"`
+	Filters       []MetricsFilter       `json:"filters"`
+	TimeDimension *MetricsTimeDimension `json:"timeDimension,omitempty"`
+	FromTimestamp time.Time             `json:"fromTimestamp"`
+	ToTimestamp   time.Time             `json:"toTimestamp"`
+	OrderBy       []MetricsOrderBy      `json:"orderBy,omitempty"`
+}
+
+// NewMetricsQuery creates a query over view for the given time range
+func NewMetricsQuery(view MetricsView, from, to time.Time) *MetricsQuery {
+	return &MetricsQuery{
+		View:          view,
+		Dimensions:    []MetricsDimension{},
+		Metrics:       []MetricsMetric{},
+		Filters:       []MetricsFilter{},
+		FromTimestamp: from.UTC(),
+		ToTimestamp:   to.UTC(),
+	}
+}
+
+// GroupBy adds dimensions to group the results by
+func (q *MetricsQuery) GroupBy(fields ...string) *MetricsQuery {
+	for _, field := range fields {
+		q.Dimensions = append(q.Dimensions, MetricsDimension{Field: field})
+	}
+	return q
+}
+
+// Metric adds a measure with the given aggregation to the results
+func (q *MetricsQuery) Metric(measure string, aggregation MetricsAggregation) *MetricsQuery {
+	q.Metrics = append(q.Metrics, MetricsMetric{Measure: measure, Aggregation: aggregation})
+	return q
+}
+
+// WhereString adds a filter on a string column (e.g. operator "=" or "contains")
+func (q *MetricsQuery) WhereString(column, operator, value string) *MetricsQuery {
+	q.Filters = append(q.Filters, MetricsFilter{Column: column, Operator: operator, Value: value, Type: "string"})
+	return q
+}
+
+// WhereAnyOf adds a filter matching rows whose column is one of values
+func (q *MetricsQuery) WhereAnyOf(column string, values ...string) *MetricsQuery {
+	q.Filters = append(q.Filters, MetricsFilter{Column: column, Operator: "any of", Value: values, Type: "stringOptions"})
+	return q
+}
+
+// WhereTags adds a filter matching rows that have any of the given tags
+func (q *MetricsQuery) WhereTags(tags ...string) *MetricsQuery {
+	q.Filters = append(q.Filters, MetricsFilter{Column: "tags", Operator: "any of", Value: tags, Type: "arrayOptions"})
+	return q
+}
+
+// WhereNumber adds a filter on a numeric column (e.g. operator ">=")
+func (q *MetricsQuery) WhereNumber(column, operator string, value float64) *MetricsQuery {
+	q.Filters = append(q.Filters, MetricsFilter{Column: column, Operator: operator, Value: value, Type: "number"})
+	return q
+}
+
+// Granularity groups the results into time buckets of the given size
+func (q *MetricsQuery) Granularity(granularity MetricsGranularity) *MetricsQuery {
+	q.TimeDimension = &MetricsTimeDimension{Granularity: granularity}
+	return q
+}
+
+// OrderByField sorts the results by field in the given direction ("asc" or "desc")
+func (q *MetricsQuery) OrderByField(field, direction string) *MetricsQuery {
+	q.OrderBy = append(q.OrderBy, MetricsOrderBy{Field: field, Direction: direction})
+	return q
+}
+
+// MetricsResult contains the rows returned by a metrics query.
+// Each row maps dimension fields and "<aggregation>_<measure>" keys to values.
+type MetricsResult struct {
+	Data []map[string]any `json:"data"`
+}
@@ -1,8 +1,13 @@
 package model
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/optible/langfuse-go/internal/pkg/mustache"
 )
 
 type IngestionEventType string
@@ -84,6 +89,41 @@ type Generation struct {
 	PromptVersion int    `json:"promptVersion,omitempty"`
 }
 
+// WithPrompt links the generation to the prompt version that produced it.
+// Fallback prompts are not linked, as they may not exist in Langfuse. For
+// prompts returned by GetPrompt, the prompt source and fetch error are added
+// to the metadata, if it is nil or a map.
+func (g *Generation) WithPrompt(p *Prompt) *Generation {
+	if !p.IsFallback {
+		g.PromptName = p.GetName()
+		g.PromptVersion = p.GetVersion()
+	}
+
+	if p.Source == "" {
+		return g
+	}
+
+	var metadata map[string]any
+	switch m := g.Metadata.(type) {
+	case nil:
+		metadata = map[string]any{}
+		g.Metadata = metadata
+	case map[string]any:
+		metadata = m
+	case M:
+		metadata = m
+	default:
+		return g
+	}
+
+	metadata["promptSource"] = string(p.Source)
+	if p.FetchError != nil {
+		metadata["promptFetchError"] = p.FetchError.Error()
+	}
+
+	return g
+}
+
 type Usage struct {
 	Input      int       `json:"input,omitempty"`
 	Output     int       `json:"output,omitempty"`
@@ -109,13 +149,47 @@ const (
 )
 
 type Score struct {
-	ID            string  `json:"id,omitempty"`
-	TraceID       string  `json:"traceId,omitempty"`
-	Name          string  `json:"name,omitempty"`
-	Value         float64 `json:"value,omitempty"`
-	ObservationID string  `json:"observationId,omitempty"`
-	Comment       string  `json:"comment,omitempty"`
-	SessionID     string  `json:"sessionId,omitempty"`
+	ID            string        `json:"id,omitempty"`
+	TraceID       string        `json:"traceId,omitempty"`
+	Name          string        `json:"name,omitempty"`
+	Value         float64       `json:"value"`
+	ObservationID string        `json:"observationId,omitempty"`
+	Comment       string        `json:"comment,omitempty"`
+	SessionID     string        `json:"sessionId,omitempty"`
+	DataType      ScoreDataType `json:"dataType,omitempty"`
+	ConfigID      string        `json:"configId,omitempty"`
+	Metadata      any           `json:"metadata,omitempty"`
+	Environment   string        `json:"environment,omitempty"`
+
+	// The following fields are only populated by the read API.
+	StringValue  string      `json:"stringValue,omitempty"`
+	Source       ScoreSource `json:"source,omitempty"`
+	Timestamp    *time.Time  `json:"timestamp,omitempty"`
+	AuthorUserID string      `json:"authorUserId,omitempty"`
+	CreatedAt    *time.Time  `json:"createdAt,omitempty"`
+	UpdatedAt    *time.Time  `json:"updatedAt,omitempty"`
+}
+
+type ScoreDataType string
+
+const (
+	ScoreDataTypeNumeric     ScoreDataType = "NUMERIC"
+	ScoreDataTypeCategorical ScoreDataType = "CATEGORICAL"
+	ScoreDataTypeBoolean     ScoreDataType = "BOOLEAN"
+)
+
+type ScoreSource string
+
+const (
+	ScoreSourceAPI        ScoreSource = "API"
+	ScoreSourceAnnotation ScoreSource = "ANNOTATION"
+	ScoreSourceEval       ScoreSource = "EVAL"
+)
+
+// ScoreList is a single page of scores
+type ScoreList struct {
+	Data []Score        `json:"data"`
+	Meta PaginationMeta `json:"meta"`
 }
 
 type Span struct {
@@ -157,71 +231,234 @@ const (
 	PromptTypeChat PromptType = "chat"
 )
 
-// ChatMessage represents a single message in a chat prompt
-type ChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
 // TextPrompt represents a text-based prompt
 type TextPrompt struct {
-	Name            string         `json:"name"`
-	Version         int            `json:"version"`
-	Config          any            `json:"config"`
-	Labels          []string       `json:"labels"`
-	Tags            []string       `json:"tags"`
-	Prompt          string         `json:"prompt"`
-	Type            PromptType     `json:"type"`
-	CommitMessage   *string        `json:"commitMessage,omitempty"`
-	ResolutionGraph map[string]any `json:"resolutionGraph,omitempty"`
-}
-
-// Compile replaces variables in the prompt with the provided values.
-// Variables are in the format {{variableName}}.
-func (p *TextPrompt) Compile(variables map[string]string) string {
-	result := p.Prompt
-	for key, value := range variables {
-		placeholder := "{{" + key + "}}"
-		result = strings.ReplaceAll(result, placeholder, value)
+	ID              string           `json:"id,omitempty"`
+	Name            string           `json:"name"`
+	Version         int              `json:"version"`
+	Config          any              `json:"config"`
+	Labels          []string         `json:"labels"`
+	Tags            []string         `json:"tags"`
+	Prompt          string           `json:"prompt"`
+	Type            PromptType       `json:"type"`
+	CommitMessage   *string          `json:"commitMessage,omitempty"`
+	ResolutionGraph *ResolutionGraph `json:"resolutionGraph,omitempty"`
+}
+
+// Compile renders the prompt as a mustache template with the provided values.
+// Variables are in the format {{variableName}} and may be surrounded by
+// whitespace; sections ({{#list}}...{{/list}}) and inverted sections are
+// supported and values are never HTML-escaped. Variables without a value are
+// kept as written, and so are malformed tags such as a stray "{{".
+func (p *TextPrompt) Compile(variables map[string]any) string {
+	result, _ := compileTemplate(p.Prompt, variables, false)
+	return result.Output
+}
+
+// Variables returns the names of the variables used by the prompt, in order
+// of first appearance, including the names used inside sections.
+func (p *TextPrompt) Variables() []string {
+	return templateVariables(p.Prompt)
+}
+
+// CompileStrict renders the prompt like Compile, but returns a
+// *VariablesError if a variable has no value or a value is not used, and an
+// error if the template is malformed. Section names without a value are
+// reported missing, and so are the variables inside those sections.
+func (p *TextPrompt) CompileStrict(variables map[string]any) (string, error) {
+	result, err := compileTemplate(p.Prompt, variables, true)
+	if err != nil {
+		return "", err
 	}
-	return result
+
+	if err := checkVariables(result.Missing, result.Unused(variables)); err != nil {
+		return "", err
+	}
+	return result.Output, nil
 }
 
 // ChatPrompt represents a chat-based prompt with multiple messages
 type ChatPrompt struct {
-	Name            string         `json:"name"`
-	Version         int            `json:"version"`
-	Config          any            `json:"config"`
-	Labels          []string       `json:"labels"`
-	Tags            []string       `json:"tags"`
-	Prompt          []ChatMessage  `json:"prompt"`
-	Type            PromptType     `json:"type"`
-	CommitMessage   *string        `json:"commitMessage,omitempty"`
-	ResolutionGraph map[string]any `json:"resolutionGraph,omitempty"`
-}
-
-// Compile replaces variables in all chat messages with the provided values.
-// Variables are in the format {{variableName}}.
-func (p *ChatPrompt) Compile(variables map[string]string) []ChatMessage {
-	result := make([]ChatMessage, len(p.Prompt))
+	ID              string           `json:"id,omitempty"`
+	Name            string           `json:"name"`
+	Version         int              `json:"version"`
+	Config          any              `json:"config"`
+	Labels          []string         `json:"labels"`
+	Tags            []string         `json:"tags"`
+	Prompt          []ChatMessage    `json:"prompt"`
+	Type            PromptType       `json:"type"`
+	CommitMessage   *string          `json:"commitMessage,omitempty"`
+	ResolutionGraph *ResolutionGraph `json:"resolutionGraph,omitempty"`
+}
+
+// Compile renders the content of every chat message as a mustache template
+// with the provided values, see TextPrompt.Compile. In multimodal messages
+// only text parts are rendered. Placeholders are replaced by the
+// messages with the same name in placeholders; placeholders without messages
+// are kept as is.
+func (p *ChatPrompt) Compile(variables map[string]any, placeholders map[string][]ChatMessage) []ChatMessage {
+	result := make([]ChatMessage, 0, len(p.Prompt))
+	for _, msg := range p.Prompt {
+		if msg.IsPlaceholder() {
+			if messages, ok := placeholders[msg.Name]; ok {
+				result = append(result, messages...)
+			} else {
+				result = append(result, msg)
+			}
+			continue
+		}
+
+		compiled, _, _ := compileMessage(msg, variables, false)
+		result = append(result, compiled)
+	}
+	return result
+}
+
+// Variables returns the names of the variables used by any message, see
+// TextPrompt.Variables.
+func (p *ChatPrompt) Variables() []string {
+	var names []string
+	for _, msg := range p.Prompt {
+		if msg.IsPlaceholder() {
+			continue
+		}
+		for _, text := range msg.templates() {
+			names = appendUnique(names, templateVariables(text)...)
+		}
+	}
+	return names
+}
+
+// Placeholders returns the names of the placeholders in the prompt
+func (p *ChatPrompt) Placeholders() []string {
+	var names []string
+	for _, msg := range p.Prompt {
+		if msg.IsPlaceholder() {
+			names = appendUnique(names, msg.Name)
+		}
+	}
+	return names
+}
+
+// CompileStrict renders every chat message like Compile, but returns a
+// *VariablesError if a variable or placeholder has no value or a value is
+// not used by any message, and an error if a template is malformed.
+func (p *ChatPrompt) CompileStrict(variables map[string]any, placeholders map[string][]ChatMessage) ([]ChatMessage, error) {
+	result := make([]ChatMessage, 0, len(p.Prompt))
+	varsErr := &VariablesError{}
+	used := map[string]bool{}
+	usedPlaceholders := map[string]bool{}
+
 	for i, msg := range p.Prompt {
-		content := msg.Content
-		for key, value := range variables {
-			placeholder := "{{" + key + "}}"
-			content = strings.ReplaceAll(content, placeholder, value)
+		if msg.IsPlaceholder() {
+			messages, ok := placeholders[msg.Name]
+			if !ok {
+				varsErr.MissingPlaceholders = appendUnique(varsErr.MissingPlaceholders, msg.Name)
+			}
+			usedPlaceholders[msg.Name] = true
+			result = append(result, messages...)
+			continue
 		}
-		result[i] = ChatMessage{
-			Role:    msg.Role,
-			Content: content,
+
+		compiled, results, err := compileMessage(msg, variables, true)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+
+		for _, r := range results {
+			varsErr.Missing = appendUnique(varsErr.Missing, r.Missing...)
+			for key := range r.Used {
+				used[key] = true
+			}
 		}
+
+		result = append(result, compiled)
 	}
-	return result
+
+	varsErr.Extra = (&mustache.Result{Used: used}).Unused(variables)
+	for name := range placeholders {
+		if !usedPlaceholders[name] {
+			varsErr.ExtraPlaceholders = append(varsErr.ExtraPlaceholders, name)
+		}
+	}
+	sort.Strings(varsErr.ExtraPlaceholders)
+
+	if varsErr.empty() {
+		return result, nil
+	}
+	return nil, varsErr
 }
 
 // Prompt is a union type that can be either TextPrompt or ChatPrompt
 type Prompt struct {
 	*TextPrompt
 	*ChatPrompt
+
+	// IsFallback is true if the prompt is a fallback returned because the
+	// prompt could not be fetched from Langfuse
+	IsFallback bool
+
+	// Source tells where a prompt returned by GetPrompt came from
+	Source PromptSource
+
+	// FetchError is the error of fetching the prompt from Langfuse, for
+	// fallbacks and for stale prompts whose refresh failed
+	FetchError error
+}
+
+// PromptSource tells where a prompt returned by GetPrompt came from
+type PromptSource string
+
+const (
+	// PromptSourceFresh is a prompt fetched from Langfuse by the call
+	PromptSourceFresh PromptSource = "fresh"
+	// PromptSourceCached is an unexpired prompt from the cache
+	PromptSourceCached PromptSource = "cached"
+	// PromptSourceStale is an expired prompt from the cache, returned while
+	// it is refreshed in the background
+	PromptSourceStale PromptSource = "stale"
+	// PromptSourceFallback is a fallback prompt returned because the prompt
+	// could not be fetched
+	PromptSourceFallback PromptSource = "fallback"
+)
+
+// MarshalJSON encodes the prompt in the format of the Langfuse API, with the
+// type set according to the kind of prompt
+func (p Prompt) MarshalJSON() ([]byte, error) {
+	switch {
+	case p.TextPrompt != nil:
+		text := *p.TextPrompt
+		text.Type = PromptTypeText
+		return json.Marshal(text)
+	case p.ChatPrompt != nil:
+		chat := *p.ChatPrompt
+		chat.Type = PromptTypeChat
+		return json.Marshal(chat)
+	default:
+		return []byte("null"), nil
+	}
+}
+
+// UnmarshalJSON decodes a text or chat prompt depending on its type
+func (p *Prompt) UnmarshalJSON(data []byte) error {
+	var typed struct {
+		Type PromptType `json:"type"`
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+
+	*p = Prompt{}
+	switch typed.Type {
+	case PromptTypeText:
+		p.TextPrompt = &TextPrompt{}
+		return json.Unmarshal(data, p.TextPrompt)
+	case PromptTypeChat:
+		p.ChatPrompt = &ChatPrompt{}
+		return json.Unmarshal(data, p.ChatPrompt)
+	default:
+		return fmt.Errorf("unknown prompt type: %s", typed.Type)
+	}
 }
 
 // IsText returns true if the prompt is a text prompt
@@ -256,6 +493,17 @@ func (p *Prompt) GetVersion() int {
 	return 0
 }
 
+// Variables returns the names of the variables used by the prompt
+func (p *Prompt) Variables() []string {
+	if p.TextPrompt != nil {
+		return p.TextPrompt.Variables()
+	}
+	if p.ChatPrompt != nil {
+		return p.ChatPrompt.Variables()
+	}
+	return nil
+}
+
 // GetConfig returns the prompt config
 func (p *Prompt) GetConfig() any {
 	if p.TextPrompt != nil {
@@ -289,3 +537,101 @@ func (p *Prompt) GetTags() []string {
 	return nil
 }
 
+// PromptMeta describes a prompt and its versions as returned when listing prompts
+type PromptMeta struct {
+	Name          string     `json:"name"`
+	Type          PromptType `json:"type,omitempty"`
+	Versions      []int      `json:"versions"`
+	Labels        []string   `json:"labels"`
+	Tags          []string   `json:"tags"`
+	LastUpdatedAt *time.Time `json:"lastUpdatedAt,omitempty"`
+	LastConfig    any        `json:"lastConfig,omitempty"`
+}
+
+// PromptMetaList is a single page of prompt metadata
+type PromptMetaList struct {
+	Data []PromptMeta   `json:"data"`
+	Meta PaginationMeta `json:"meta"`
+}
+
+// compileTemplate parses and renders a prompt template. Malformed tags are
+// an error if strict is set and kept as literal text otherwise.
+func compileTemplate(template string, variables map[string]any, strict bool) (*mustache.Result, error) {
+	if !strict {
+		return mustache.ParseLenient(template).Render(variables), nil
+	}
+
+	tmpl, err := mustache.Parse(template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template: %w", err)
+	}
+	return tmpl.Render(variables), nil
+}
+
+// VariablesError is returned by CompileStrict if the variables or
+// placeholders do not match the ones used by the prompt
+type VariablesError struct {
+	// Missing lists the variables used by the prompt without a value
+	Missing []string
+
+	// Extra lists the provided variables not used by the prompt
+	Extra []string
+
+	// MissingPlaceholders lists the placeholders of a chat prompt without messages
+	MissingPlaceholders []string
+
+	// ExtraPlaceholders lists the provided placeholders not used by a chat prompt
+	ExtraPlaceholders []string
+}
+
+func (e *VariablesError) Error() string {
+	var problems []string
+	add := func(kind string, names []string) {
+		if len(names) > 0 {
+			problems = append(problems, kind+": "+strings.Join(names, ", "))
+		}
+	}
+
+	add("missing variables", e.Missing)
+	add("extra variables", e.Extra)
+	add("missing placeholders", e.MissingPlaceholders)
+	add("extra placeholders", e.ExtraPlaceholders)
+
+	return strings.Join(problems, "; ")
+}
+
+func (e *VariablesError) empty() bool {
+	return len(e.Missing) == 0 && len(e.Extra) == 0 &&
+		len(e.MissingPlaceholders) == 0 && len(e.ExtraPlaceholders) == 0
+}
+
+// templateVariables returns the variables of a template, ignoring malformed tags
+func templateVariables(template string) []string {
+	return mustache.ParseLenient(template).Variables()
+}
+
+// checkVariables returns a *VariablesError if any variable is missing or unused
+func checkVariables(missing, unused []string) error {
+	err := &VariablesError{Missing: missing, Extra: unused}
+	if err.empty() {
+		return nil
+	}
+	return err
+}
+
+// appendUnique appends the values not yet contained in list
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
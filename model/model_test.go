@@ -1,6 +1,9 @@
 package model
 
 import (
+	"encoding/json"
+	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -12,7 +15,7 @@ func TestTextPrompt_Compile(t *testing.T) {
 		Type:    PromptTypeText,
 	}
 
-	result := prompt.Compile(map[string]string{
+	result := prompt.Compile(map[string]any{
 		"name":   "Alice",
 		"role":   "helpful assistant",
 		"action": "summarize the document",
@@ -32,7 +35,7 @@ func TestTextPrompt_Compile_NoVariables(t *testing.T) {
 		Type:    PromptTypeText,
 	}
 
-	result := prompt.Compile(map[string]string{})
+	result := prompt.Compile(map[string]any{})
 	expected := "Hello world!"
 	if result != expected {
 		t.Errorf("expected %q, got %q", expected, result)
@@ -47,7 +50,7 @@ func TestTextPrompt_Compile_UnusedVariables(t *testing.T) {
 		Type:    PromptTypeText,
 	}
 
-	result := prompt.Compile(map[string]string{
+	result := prompt.Compile(map[string]any{
 		"name":  "Bob",
 		"extra": "ignored",
 	})
@@ -66,7 +69,7 @@ func TestTextPrompt_Compile_MissingVariables(t *testing.T) {
 		Type:    PromptTypeText,
 	}
 
-	result := prompt.Compile(map[string]string{
+	result := prompt.Compile(map[string]any{
 		"name": "Charlie",
 	})
 
@@ -88,11 +91,11 @@ func TestChatPrompt_Compile(t *testing.T) {
 		Type: PromptTypeChat,
 	}
 
-	result := prompt.Compile(map[string]string{
+	result := prompt.Compile(map[string]any{
 		"role":   "helpful assistant",
 		"name":   "Alice",
 		"action": "help me",
-	})
+	}, nil)
 
 	if len(result) != 2 {
 		t.Errorf("expected 2 messages, got %d", len(result))
@@ -207,3 +210,246 @@ func TestPrompt_GetTags(t *testing.T) {
 		t.Errorf("unexpected tags: %v", tags)
 	}
 }
+
+func TestTextPrompt_Compile_Mustache(t *testing.T) {
+	prompt := &TextPrompt{
+		Prompt: "Hi {{ name }}, you have {{count}} items:\n{{#items}}\n- {{.}}\n{{/items}}\n{{^items}}\nnothing\n{{/items}}",
+	}
+
+	result := prompt.Compile(map[string]any{
+		"name":  "Dana",
+		"count": 2,
+		"items": []string{"<a>", "b"},
+	})
+
+	expected := "Hi Dana, you have 2 items:\n- <a>\n- b\n"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestTextPrompt_Compile_StrayBraces(t *testing.T) {
+	prompt := &TextPrompt{Prompt: "Hello {{name}}, write {{ in code and {{}} too"}
+
+	expected := "Hello Ann, write {{ in code and {{}} too"
+	if result := prompt.Compile(map[string]any{"name": "Ann"}); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+
+	if _, err := prompt.CompileStrict(map[string]any{"name": "Ann"}); err == nil {
+		t.Error("expected strict compile to reject the malformed template")
+	}
+
+	chat := &ChatPrompt{Prompt: []ChatMessage{{Role: "user", Content: "{{question}} {{"}}}
+	if messages := chat.Compile(map[string]any{"question": "Why?"}, nil); messages[0].Content != "Why? {{" {
+		t.Errorf("unexpected messages %+v", messages)
+	}
+}
+
+func TestTextPrompt_CompileStrict(t *testing.T) {
+	prompt := &TextPrompt{Prompt: "Hello {{name}}, your role is {{role}}!"}
+
+	result, err := prompt.CompileStrict(map[string]any{"name": "A", "role": "B"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result != "Hello A, your role is B!" {
+		t.Errorf("unexpected result %q", result)
+	}
+
+	_, err = prompt.CompileStrict(map[string]any{"name": "A", "extra": 1})
+	if err == nil || err.Error() != "missing variables: role; extra variables: extra" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	_, err = (&TextPrompt{Prompt: "{{#open}}"}).CompileStrict(nil)
+	if err == nil {
+		t.Error("expected error for malformed template")
+	}
+}
+
+func TestChatPrompt_CompileStrict(t *testing.T) {
+	prompt := &ChatPrompt{
+		Prompt: []ChatMessage{
+			{Role: "system", Content: "You are a {{role}}."},
+			{Role: "user", Content: "{{question}}"},
+		},
+	}
+
+	_, err := prompt.CompileStrict(map[string]any{"role": "tutor", "question": "Why?"}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = prompt.CompileStrict(map[string]any{"role": "tutor", "unused": true}, nil)
+	if err == nil || err.Error() != "missing variables: question; extra variables: unused" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestCompileStrict_VariablesError(t *testing.T) {
+	prompt := &TextPrompt{Prompt: "{{a}} {{b}}"}
+
+	_, err := prompt.CompileStrict(map[string]any{"a": 1, "c": 2})
+
+	var varsErr *VariablesError
+	if !errors.As(err, &varsErr) {
+		t.Fatalf("expected *VariablesError, got %v", err)
+	}
+	if len(varsErr.Missing) != 1 || varsErr.Missing[0] != "b" {
+		t.Errorf("unexpected missing variables %v", varsErr.Missing)
+	}
+	if len(varsErr.Extra) != 1 || varsErr.Extra[0] != "c" {
+		t.Errorf("unexpected extra variables %v", varsErr.Extra)
+	}
+}
+
+func TestCompileStrict_MissingMatchesVariables(t *testing.T) {
+	prompt := &TextPrompt{Prompt: "{{#user}}Hi {{name}}{{/user}}{{^admin}}{{role}}{{/admin}} {{topic}}"}
+
+	_, err := prompt.CompileStrict(nil)
+
+	var varsErr *VariablesError
+	if !errors.As(err, &varsErr) {
+		t.Fatalf("expected *VariablesError, got %v", err)
+	}
+	if !reflect.DeepEqual(varsErr.Missing, prompt.Variables()) {
+		t.Errorf("expected missing variables %v, got %v", prompt.Variables(), varsErr.Missing)
+	}
+
+	variables := map[string]any{}
+	for _, name := range prompt.Variables() {
+		variables[name] = "x"
+	}
+	if _, err := prompt.CompileStrict(variables); err != nil {
+		t.Errorf("expected no error with all variables, got %v", err)
+	}
+}
+
+func TestPrompt_Variables(t *testing.T) {
+	text := &Prompt{TextPrompt: &TextPrompt{Prompt: "{{ name }} {{#items}}{{title}}{{/items}} {{user.id}} {{name}}"}}
+	if vars := text.Variables(); !reflect.DeepEqual(vars, []string{"name", "items", "title", "user"}) {
+		t.Errorf("unexpected text variables %v", vars)
+	}
+
+	chat := &Prompt{ChatPrompt: &ChatPrompt{Prompt: []ChatMessage{
+		{Role: "system", Content: "You are a {{role}}."},
+		{Role: "user", Content: "{{question}} ({{role}})"},
+	}}}
+	if vars := chat.Variables(); !reflect.DeepEqual(vars, []string{"role", "question"}) {
+		t.Errorf("unexpected chat variables %v", vars)
+	}
+
+	if vars := (&Prompt{}).Variables(); vars != nil {
+		t.Errorf("expected no variables, got %v", vars)
+	}
+}
+
+func TestChatPrompt_CompilePlaceholders(t *testing.T) {
+	prompt := &ChatPrompt{
+		Prompt: []ChatMessage{
+			{Role: "system", Content: "You are a {{role}}."},
+			{Type: ChatMessageTypePlaceholder, Name: "history"},
+			{Role: "user", Content: "{{question}}"},
+		},
+	}
+	history := []ChatMessage{
+		{Role: "user", Content: "Hi {{role}}"},
+		{Role: "assistant", Content: "Hello!"},
+	}
+
+	result := prompt.Compile(map[string]any{"role": "tutor", "question": "Why?"}, map[string][]ChatMessage{
+		"history": history,
+	})
+
+	expected := []ChatMessage{
+		{Role: "system", Content: "You are a tutor."},
+		{Role: "user", Content: "Hi {{role}}"},
+		{Role: "assistant", Content: "Hello!"},
+		{Role: "user", Content: "Why?"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+
+	// Placeholders without messages are kept
+	result = prompt.Compile(nil, nil)
+	if len(result) != 3 || !result[1].IsPlaceholder() || result[1].Name != "history" {
+		t.Errorf("expected placeholder to be kept, got %+v", result)
+	}
+
+	if names := prompt.Placeholders(); !reflect.DeepEqual(names, []string{"history"}) {
+		t.Errorf("unexpected placeholders %v", names)
+	}
+}
+
+func TestChatPrompt_CompileStrictPlaceholders(t *testing.T) {
+	prompt := &ChatPrompt{
+		Prompt: []ChatMessage{
+			{Type: ChatMessageTypePlaceholder, Name: "history"},
+			{Role: "user", Content: "Hi"},
+		},
+	}
+
+	result, err := prompt.CompileStrict(nil, map[string][]ChatMessage{"history": nil})
+	if err != nil || len(result) != 1 {
+		t.Fatalf("expected empty history to be accepted, got %+v, %v", result, err)
+	}
+
+	_, err = prompt.CompileStrict(nil, map[string][]ChatMessage{"other": nil})
+	if err == nil || err.Error() != "missing placeholders: history; extra placeholders: other" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestChatMessage_JSON(t *testing.T) {
+	var messages []ChatMessage
+	data := `[{"role":"system","content":"Hi"},{"type":"placeholder","name":"history"}]`
+	if err := json.Unmarshal([]byte(data), &messages); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if len(messages) != 2 || messages[0].IsPlaceholder() || !messages[1].IsPlaceholder() || messages[1].Name != "history" {
+		t.Fatalf("unexpected messages %+v", messages)
+	}
+
+	encoded, err := json.Marshal(messages)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if string(encoded) != data {
+		t.Errorf("expected %s, got %s", data, encoded)
+	}
+}
+
+func TestPrompt_JSONRoundTrip(t *testing.T) {
+	prompts := []*Prompt{
+		{TextPrompt: &TextPrompt{Name: "text", Version: 2, Prompt: "Hi {{name}}", Labels: []string{"production"}}},
+		{ChatPrompt: &ChatPrompt{Name: "chat", Version: 1, Config: map[string]any{"model": "gpt-4o"}, Prompt: []ChatMessage{
+			{Role: "system", Content: "Be brief."},
+			{Type: ChatMessageTypePlaceholder, Name: "history"},
+		}}},
+	}
+
+	for _, prompt := range prompts {
+		data, err := json.Marshal(prompt)
+		if err != nil {
+			t.Fatalf("failed to marshal: %v", err)
+		}
+
+		var decoded Prompt
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("failed to unmarshal %s: %v", data, err)
+		}
+		if decoded.GetName() != prompt.GetName() || decoded.GetVersion() != prompt.GetVersion() || decoded.IsChat() != prompt.IsChat() {
+			t.Errorf("unexpected prompt %s", data)
+		}
+		if prompt.IsChat() && !reflect.DeepEqual(decoded.ChatPrompt.Prompt, prompt.ChatPrompt.Prompt) {
+			t.Errorf("unexpected messages %+v", decoded.ChatPrompt.Prompt)
+		}
+	}
+
+	var decoded Prompt
+	if err := json.Unmarshal([]byte(`{"type":"audio"}`), &decoded); err == nil {
+		t.Error("expected error for unknown prompt type")
+	}
+}
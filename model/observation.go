@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// ObservationType represents the kind of an observation
+type ObservationType string
+
+const (
+	ObservationTypeGeneration ObservationType = "GENERATION"
+	ObservationTypeSpan       ObservationType = "SPAN"
+	ObservationTypeEvent      ObservationType = "EVENT"
+)
+
+// Observation is a generation, span or event as returned by the read API,
+// including the usage and cost details computed by Langfuse.
+type Observation struct {
+	ID                  string           `json:"id"`
+	TraceID             string           `json:"traceId,omitempty"`
+	Type                ObservationType  `json:"type"`
+	Name                string           `json:"name,omitempty"`
+	StartTime           *time.Time       `json:"startTime,omitempty"`
+	EndTime             *time.Time       `json:"endTime,omitempty"`
+	CompletionStartTime *time.Time       `json:"completionStartTime,omitempty"`
+	Model               string           `json:"model,omitempty"`
+	ModelID             string           `json:"modelId,omitempty"`
+	ModelParameters     any              `json:"modelParameters,omitempty"`
+	Input               any              `json:"input,omitempty"`
+	Output              any              `json:"output,omitempty"`
+	Metadata            any              `json:"metadata,omitempty"`
+	Version             string           `json:"version,omitempty"`
+	Level               ObservationLevel `json:"level,omitempty"`
+	StatusMessage       string           `json:"statusMessage,omitempty"`
+	ParentObservationID string           `json:"parentObservationId,omitempty"`
+	Environment         string           `json:"environment,omitempty"`
+
+	PromptID      string `json:"promptId,omitempty"`
+	PromptName    string `json:"promptName,omitempty"`
+	PromptVersion int    `json:"promptVersion,omitempty"`
+
+	Usage        Usage              `json:"usage,omitempty"`
+	UsageDetails map[string]int     `json:"usageDetails,omitempty"`
+	CostDetails  map[string]float64 `json:"costDetails,omitempty"`
+
+	InputPrice           float64 `json:"inputPrice,omitempty"`
+	OutputPrice          float64 `json:"outputPrice,omitempty"`
+	TotalPrice           float64 `json:"totalPrice,omitempty"`
+	CalculatedInputCost  float64 `json:"calculatedInputCost,omitempty"`
+	CalculatedOutputCost float64 `json:"calculatedOutputCost,omitempty"`
+	CalculatedTotalCost  float64 `json:"calculatedTotalCost,omitempty"`
+
+	// Latency is the duration of the observation in seconds.
+	Latency float64 `json:"latency,omitempty"`
+	// TimeToFirstToken is the time until the first completion token in seconds.
+	TimeToFirstToken float64 `json:"timeToFirstToken,omitempty"`
+}
+
+// ObservationList is a single page of observations
+type ObservationList struct {
+	Data []Observation  `json:"data"`
+	Meta PaginationMeta `json:"meta"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// PaginationMeta describes the pagination state of a list response
+type PaginationMeta struct {
+	Page       int `json:"page"`
+	Limit      int `json:"limit"`
+	TotalItems int `json:"totalItems"`
+	TotalPages int `json:"totalPages"`
+}
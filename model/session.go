@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Session groups the traces sharing the same SessionID
+type Session struct {
+	ID          string     `json:"id"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	ProjectID   string     `json:"projectId,omitempty"`
+	Environment string     `json:"environment,omitempty"`
+}
+
+// SessionWithTraces is a session together with all of its traces
+type SessionWithTraces struct {
+	Session
+	Traces []Trace `json:"traces"`
+}
+
+// SessionList is a single page of sessions
+type SessionList struct {
+	Data []Session      `json:"data"`
+	Meta PaginationMeta `json:"meta"`
+}
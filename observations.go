@@ -0,0 +1,101 @@
+package langfuse
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// ObservationFilter contains the filters for listing observations.
+// Empty fields are not sent to the API.
+type ObservationFilter struct {
+	TraceID             string
+	Type                model.ObservationType
+	Name                string
+	UserID              string
+	ParentObservationID string
+	Version             string
+	Environment         []string
+
+	// FromStartTime only includes observations that started at or after this time.
+	FromStartTime *time.Time
+	// ToStartTime only includes observations that started before this time.
+	ToStartTime *time.Time
+
+	// Page is the 1-based page to fetch. Defaults to the first page.
+	Page int
+	// Limit is the number of items per page. Defaults to the API's default.
+	Limit int
+}
+
+func (f *ObservationFilter) params(page int) url.Values {
+	params := url.Values{}
+	setIntParam(params, "page", page)
+	setIntParam(params, "limit", f.Limit)
+	setParam(params, "traceId", f.TraceID)
+	setParam(params, "type", string(f.Type))
+	setParam(params, "name", f.Name)
+	setParam(params, "userId", f.UserID)
+	setParam(params, "parentObservationId", f.ParentObservationID)
+	setParam(params, "version", f.Version)
+	for _, env := range f.Environment {
+		params.Add("environment", env)
+	}
+	setTimeParam(params, "fromStartTime", f.FromStartTime)
+	setTimeParam(params, "toStartTime", f.ToStartTime)
+	return params
+}
+
+// GetObservation fetches a single observation by its ID.
+func (l *Langfuse) GetObservation(ctx context.Context, observationID string) (*model.Observation, error) {
+	if observationID == "" {
+		return nil, fmt.Errorf("observation ID is required")
+	}
+
+	observation := &model.Observation{}
+	err := l.getJSON(ctx, "/api/public/observations/"+url.PathEscape(observationID), observation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get observation: %w", err)
+	}
+
+	return observation, nil
+}
+
+// ListObservations fetches a single page of observations matching the filter.
+// Use IterObservations to walk all pages.
+func (l *Langfuse) ListObservations(ctx context.Context, filter *ObservationFilter) (*model.ObservationList, error) {
+	if filter == nil {
+		filter = &ObservationFilter{}
+	}
+
+	return l.listObservations(ctx, filter, filter.Page)
+}
+
+// IterObservations returns an iterator over all observations matching the filter,
+// starting at filter.Page.
+func (l *Langfuse) IterObservations(filter *ObservationFilter) *Iterator[model.Observation] {
+	if filter == nil {
+		filter = &ObservationFilter{}
+	}
+
+	return newIterator(filter.Page, func(ctx context.Context, page int) ([]model.Observation, model.PaginationMeta, error) {
+		list, err := l.listObservations(ctx, filter, page)
+		if err != nil {
+			return nil, model.PaginationMeta{}, err
+		}
+		return list.Data, list.Meta, nil
+	})
+}
+
+func (l *Langfuse) listObservations(ctx context.Context, filter *ObservationFilter, page int) (*model.ObservationList, error) {
+	list := &model.ObservationList{}
+	err := l.getJSON(ctx, buildPath("/api/public/observations", filter.params(page)), list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list observations: %w", err)
+	}
+
+	return list, nil
+}
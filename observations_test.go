@@ -0,0 +1,178 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// newTestClient returns a client pointing to a test server serving handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Langfuse {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	t.Setenv("LANGFUSE_HOST", server.URL)
+	t.Setenv("LANGFUSE_PUBLIC_KEY", "pk-test")
+	t.Setenv("LANGFUSE_SECRET_KEY", "sk-test")
+
+	return New(context.Background())
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v any) {
+	t.Helper()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Fatalf("failed to encode response: %v", err)
+	}
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("failed to parse time: %v", err)
+	}
+	return parsed
+}
+
+func TestGetObservation(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/observations/obs-1" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		writeJSON(t, w, map[string]any{
+			"id":                  "obs-1",
+			"traceId":             "trace-1",
+			"type":                "GENERATION",
+			"model":               "gpt-4o",
+			"usageDetails":        map[string]int{"input": 10, "output": 5},
+			"costDetails":         map[string]float64{"total": 0.25},
+			"calculatedTotalCost": 0.25,
+		})
+	})
+
+	obs, err := l.GetObservation(context.Background(), "obs-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if obs.Type != model.ObservationTypeGeneration {
+		t.Errorf("expected type GENERATION, got %q", obs.Type)
+	}
+	if obs.UsageDetails["input"] != 10 || obs.UsageDetails["output"] != 5 {
+		t.Errorf("unexpected usage details: %v", obs.UsageDetails)
+	}
+	if obs.CalculatedTotalCost != 0.25 {
+		t.Errorf("expected total cost 0.25, got %v", obs.CalculatedTotalCost)
+	}
+}
+
+func TestGetObservation_NotFound(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := l.GetObservation(context.Background(), "missing")
+	if err == nil {
+		t.Fatal("expected error for missing observation")
+	}
+}
+
+func TestGetObservation_WithEmptyID(t *testing.T) {
+	l := New(context.Background())
+
+	_, err := l.GetObservation(context.Background(), "")
+	if err == nil || err.Error() != "observation ID is required" {
+		t.Errorf("expected 'observation ID is required', got %v", err)
+	}
+}
+
+func TestListObservations_Filter(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("traceId") != "trace-1" {
+			t.Errorf("expected traceId filter, got %q", q.Get("traceId"))
+		}
+		if q.Get("type") != "GENERATION" {
+			t.Errorf("expected type filter, got %q", q.Get("type"))
+		}
+		if q.Get("fromStartTime") == "" {
+			t.Error("expected fromStartTime filter")
+		}
+		if q.Has("userId") {
+			t.Error("expected empty userId to be omitted")
+		}
+		writeJSON(t, w, map[string]any{
+			"data": []map[string]any{{"id": "obs-1"}},
+			"meta": map[string]any{"page": 1, "limit": 50, "totalItems": 1, "totalPages": 1},
+		})
+	})
+
+	from := mustParseTime(t, "2024-01-01T00:00:00Z")
+	list, err := l.ListObservations(context.Background(), &ObservationFilter{
+		TraceID:       "trace-1",
+		Type:          model.ObservationTypeGeneration,
+		FromStartTime: &from,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(list.Data) != 1 || list.Meta.TotalItems != 1 {
+		t.Errorf("unexpected list: %+v", list)
+	}
+}
+
+func TestIterObservations_WalksPages(t *testing.T) {
+	requests := 0
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		writeJSON(t, w, map[string]any{
+			"data": []map[string]any{
+				{"id": "obs-" + strconv.Itoa(page) + "a"},
+				{"id": "obs-" + strconv.Itoa(page) + "b"},
+			},
+			"meta": map[string]any{"page": page, "limit": 2, "totalItems": 6, "totalPages": 3},
+		})
+	})
+
+	observations, err := l.IterObservations(&ObservationFilter{Limit: 2}).All(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(observations) != 6 {
+		t.Fatalf("expected 6 observations, got %d", len(observations))
+	}
+	if observations[5].ID != "obs-3b" {
+		t.Errorf("expected last observation 'obs-3b', got %q", observations[5].ID)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestIterObservations_StopsOnError(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	it := l.IterObservations(nil)
+	if it.Next(context.Background()) {
+		t.Fatal("expected Next to return false")
+	}
+	if it.Err() == nil {
+		t.Error("expected iterator error")
+	}
+}
@@ -0,0 +1,196 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+const defaultPrefetchConcurrency = 8
+
+// PromptRef identifies a prompt version, e.g. to prefetch or invalidate.
+// Without version or label, it refers to the "production" version.
+type PromptRef struct {
+	Name    string
+	Label   string
+	Version int
+}
+
+func (r PromptRef) String() string {
+	switch {
+	case r.Version > 0:
+		return r.Name + "@v" + strconv.Itoa(r.Version)
+	case r.Label != "":
+		return r.Name + "@" + r.Label
+	default:
+		return r.Name
+	}
+}
+
+func (r PromptRef) options() *GetPromptOptions {
+	opts := &GetPromptOptions{ForceRefresh: true}
+	if r.Version > 0 {
+		version := r.Version
+		opts.Version = &version
+	}
+	if r.Label != "" {
+		label := r.Label
+		opts.Label = &label
+	}
+	return opts
+}
+
+// PrefetchOptions contains options for prefetching prompts
+type PrefetchOptions struct {
+	// MaxConcurrency is the number of prompts fetched at the same time.
+	// Defaults to 8.
+	MaxConcurrency int
+
+	// RefreshInterval, if set, keeps refetching the prompts in the background
+	// at this interval until the context passed to PrefetchPrompts is done.
+	// Use an interval shorter than the prompt cache TTL, so that GetPrompt
+	// always finds a fresh prompt.
+	RefreshInterval time.Duration
+
+	// OnError, if set, is called with the *PrefetchError of every background
+	// refresh that failed for some prompts. Without it, the errors are
+	// discarded.
+	OnError func(error)
+}
+
+// PromptRefError is the error of fetching a prompt to prefetch
+type PromptRefError struct {
+	Ref PromptRef
+	Err error
+}
+
+func (e *PromptRefError) Error() string {
+	return fmt.Sprintf("prompt %s: %v", e.Ref, e.Err)
+}
+
+func (e *PromptRefError) Unwrap() error {
+	return e.Err
+}
+
+// PrefetchError lists the prompts that could not be prefetched
+type PrefetchError struct {
+	Failures []*PromptRefError
+}
+
+func (e *PrefetchError) Error() string {
+	messages := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		messages[i] = failure.Error()
+	}
+	return fmt.Sprintf("failed to prefetch %d prompts: %s", len(e.Failures), strings.Join(messages, "; "))
+}
+
+func (e *PrefetchError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure
+	}
+	return errs
+}
+
+// PrefetchPrompts fetches the prompts concurrently into the prompt cache, for
+// example to warm up the cache before serving traffic. It returns a
+// *PrefetchError listing the prompts that failed, including those for which
+// a fallback prompt was returned; the others are cached.
+func (l *Langfuse) PrefetchPrompts(ctx context.Context, refs []PromptRef, opts *PrefetchOptions) error {
+	if opts == nil {
+		opts = &PrefetchOptions{}
+	}
+	for _, ref := range refs {
+		if ref.Name == "" {
+			return fmt.Errorf("prompt name is required")
+		}
+	}
+
+	err := l.prefetchPrompts(ctx, refs, opts.MaxConcurrency)
+
+	if opts.RefreshInterval > 0 {
+		refreshOpts := *opts
+		go l.refreshPromptsEvery(ctx, slices.Clone(refs), &refreshOpts)
+	}
+
+	return err
+}
+
+func (l *Langfuse) prefetchPrompts(ctx context.Context, refs []PromptRef, maxConcurrency int) error {
+	if maxConcurrency <= 0 {
+		maxConcurrency = defaultPrefetchConcurrency
+	}
+
+	failures := make([]*PromptRefError, len(refs))
+	sem := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+
+	for i, ref := range refs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, ref PromptRef) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := l.prefetchPrompt(ctx, ref); err != nil {
+				failures[i] = &PromptRefError{Ref: ref, Err: err}
+			}
+		}(i, ref)
+	}
+	wg.Wait()
+
+	prefetchErr := &PrefetchError{}
+	for _, failure := range failures {
+		if failure != nil {
+			prefetchErr.Failures = append(prefetchErr.Failures, failure)
+		}
+	}
+	if len(prefetchErr.Failures) == 0 {
+		return nil
+	}
+	return prefetchErr
+}
+
+// prefetchPrompt fetches a prompt into the cache. Getting a fallback prompt
+// instead counts as a failure, as nothing was cached.
+func (l *Langfuse) prefetchPrompt(ctx context.Context, ref PromptRef) error {
+	p, err := l.GetPrompt(ctx, ref.Name, ref.options())
+	switch {
+	case err != nil:
+		return err
+	case p.FetchError != nil:
+		return p.FetchError
+	case p.Source == model.PromptSourceFallback:
+		return errors.New("fallback prompt returned")
+	default:
+		return nil
+	}
+}
+
+// refreshPromptsEvery refetches the prompts at the refresh interval until
+// ctx is done. Prompts that fail to refresh keep their cached version.
+func (l *Langfuse) refreshPromptsEvery(ctx context.Context, refs []PromptRef, opts *PrefetchOptions) {
+	ticker := time.NewTicker(opts.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.prefetchPrompts(ctx, refs, opts.MaxConcurrency); err != nil && ctx.Err() == nil && opts.OnError != nil {
+				opts.OnError(err)
+			}
+		}
+	}
+}
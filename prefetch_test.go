@@ -0,0 +1,153 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+func TestPrefetchPrompts(t *testing.T) {
+	var requests atomic.Int32
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		name := strings.TrimPrefix(r.URL.Path, "/api/public/v2/prompts/")
+		if name == "missing" {
+			http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		writeJSON(t, w, map[string]any{"type": "text", "name": name, "version": 1, "prompt": "Hi"})
+	})
+
+	err := l.PrefetchPrompts(context.Background(), []PromptRef{
+		{Name: "greeting"},
+		{Name: "farewell", Label: "staging"},
+		{Name: "missing", Version: 3},
+	}, nil)
+
+	var prefetchErr *PrefetchError
+	if !errors.As(err, &prefetchErr) {
+		t.Fatalf("expected *PrefetchError, got %v", err)
+	}
+	if len(prefetchErr.Failures) != 1 || prefetchErr.Failures[0].Ref.Name != "missing" {
+		t.Errorf("unexpected failures %v", prefetchErr.Failures)
+	}
+	if !strings.Contains(err.Error(), "prompt missing@v3") {
+		t.Errorf("unexpected error message %q", err)
+	}
+
+	// Prefetched prompts are served from the cache
+	requests.Store(0)
+	label := "staging"
+	if _, err := l.GetPrompt(context.Background(), "greeting", nil); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if _, err := l.GetPrompt(context.Background(), "farewell", &GetPromptOptions{Label: &label}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if requests.Load() != 0 {
+		t.Errorf("expected prompts to be cached, got %d requests", requests.Load())
+	}
+}
+
+func TestPrefetchPrompts_RefreshInterval(t *testing.T) {
+	var version atomic.Int32
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"type": "text", "name": "greeting", "version": version.Add(1), "prompt": "Hi"})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := l.PrefetchPrompts(ctx, []PromptRef{{Name: "greeting"}}, &PrefetchOptions{RefreshInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for version.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	prompt, err := l.GetPrompt(context.Background(), "greeting", nil)
+	if err != nil || prompt.GetVersion() < 2 {
+		t.Errorf("expected refreshed prompt, got %+v, error %v", prompt, err)
+	}
+
+	// Cancelling the context stops the refresh
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	stopped := version.Load()
+	time.Sleep(20 * time.Millisecond)
+	if version.Load() != stopped {
+		t.Error("expected refresh to stop after cancel")
+	}
+}
+
+func TestPrefetchPrompts_RefreshOnError(t *testing.T) {
+	var failing atomic.Bool
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(t, w, map[string]any{"type": "text", "name": "greeting", "version": 1, "prompt": "Hi"})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs := make(chan error, 1)
+	opts := &PrefetchOptions{
+		RefreshInterval: 5 * time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	}
+	if err := l.PrefetchPrompts(ctx, []PromptRef{{Name: "greeting"}}, opts); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	failing.Store(true)
+
+	select {
+	case err := <-errs:
+		var prefetchErr *PrefetchError
+		if !errors.As(err, &prefetchErr) || len(prefetchErr.Failures) != 1 {
+			t.Errorf("expected a prefetch error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected OnError to be called")
+	}
+}
+
+func TestPrefetchPrompts_WithoutName(t *testing.T) {
+	l := New(context.Background())
+
+	if err := l.PrefetchPrompts(context.Background(), []PromptRef{{Label: "production"}}, nil); err == nil {
+		t.Error("expected error without prompt name")
+	}
+}
+
+func TestPrefetchPrompts_FallbackIsFailure(t *testing.T) {
+	l := newFailingTestClient(t)
+	l.AddFallbackPrompt(&model.Prompt{TextPrompt: &model.TextPrompt{Name: "greeting", Prompt: "Hi"}})
+
+	err := l.PrefetchPrompts(context.Background(), []PromptRef{{Name: "greeting"}}, nil)
+
+	var prefetchErr *PrefetchError
+	if !errors.As(err, &prefetchErr) || len(prefetchErr.Failures) != 1 {
+		t.Fatalf("expected prefetch with a fallback to fail, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the fetch error, got %v", err)
+	}
+}
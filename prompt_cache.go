@@ -0,0 +1,138 @@
+package langfuse
+
+import (
+	"context"
+	"time"
+
+	"github.com/optible/langfuse-go/internal/pkg/cache"
+	"github.com/optible/langfuse-go/model"
+)
+
+// PromptCache stores fetched prompts by cache key. The client keeps an
+// in-memory cache by default; implementations backed by the filesystem or a
+// shared store such as Redis let cached prompts survive restarts and be
+// shared across replicas. Implementations must be safe for concurrent use.
+//
+// Errors returned by Get are treated as cache misses and errors returned by
+// Set as a failure to cache, so a cache outage does not fail GetPrompt.
+type PromptCache interface {
+	// Get returns the cached prompt for key, or nil if it is not cached.
+	// Expired entries should be returned, so that they can be served while
+	// the prompt is refreshed.
+	Get(ctx context.Context, key string) (*CachedPrompt, error)
+
+	// Set stores the prompt for key
+	Set(ctx context.Context, key string, entry *CachedPrompt) error
+
+	// Delete removes the prompt for key
+	Delete(ctx context.Context, key string) error
+
+	// Clear removes all cached prompts
+	Clear(ctx context.Context) error
+}
+
+// CachedPrompt is a prompt stored in a PromptCache
+type CachedPrompt struct {
+	Prompt    *model.Prompt `json:"prompt"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+}
+
+// IsExpired returns true if the cached prompt should be refreshed
+func (c *CachedPrompt) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
+
+// PromptCacheStats contains usage statistics of the in-memory prompt cache
+type PromptCacheStats struct {
+	// Hits counts lookups that found a prompt, expired or not
+	Hits uint64
+	// Misses counts lookups that found no prompt
+	Misses uint64
+	// Evictions counts prompts removed to stay within the max entries
+	Evictions uint64
+	// Expirations counts prompts removed after being stale for too long
+	Expirations uint64
+	// Size is the number of cached prompts
+	Size int
+}
+
+// WithPromptCache replaces the in-memory prompt cache
+func (l *Langfuse) WithPromptCache(c PromptCache) *Langfuse {
+	l.promptCache = c
+	return l
+}
+
+// WithPromptCacheMaxEntries limits the number of prompts in the in-memory
+// cache, evicting the least recently used prompts above the limit. Zero
+// means no limit. Default is 1000. It has no effect on a cache set with
+// WithPromptCache.
+func (l *Langfuse) WithPromptCacheMaxEntries(n int) *Langfuse {
+	if c, ok := l.promptCache.(*memoryPromptCache); ok {
+		c.cache.SetMaxEntries(n)
+	}
+	return l
+}
+
+// WithPromptCacheMaxStale sets how long expired prompts are kept in the
+// in-memory cache, where they are served while being refreshed, before a
+// background janitor removes them. Default is 24 hours. It has no effect on
+// a cache set with WithPromptCache.
+func (l *Langfuse) WithPromptCacheMaxStale(d time.Duration) *Langfuse {
+	if c, ok := l.promptCache.(*memoryPromptCache); ok {
+		c.cache.SetMaxStale(d)
+	}
+	return l
+}
+
+// PromptCacheStats returns usage statistics of the in-memory prompt cache.
+// It returns zero statistics for a cache set with WithPromptCache.
+func (l *Langfuse) PromptCacheStats() PromptCacheStats {
+	c, ok := l.promptCache.(*memoryPromptCache)
+	if !ok {
+		return PromptCacheStats{}
+	}
+
+	stats := c.cache.Stats()
+	return PromptCacheStats{
+		Hits:        stats.Hits,
+		Misses:      stats.Misses,
+		Evictions:   stats.Evictions,
+		Expirations: stats.Expirations,
+		Size:        stats.Size,
+	}
+}
+
+// memoryPromptCache is the default in-memory PromptCache
+type memoryPromptCache struct {
+	cache *cache.Cache[*model.Prompt]
+}
+
+func newMemoryPromptCache(ttl time.Duration) *memoryPromptCache {
+	c := cache.New[*model.Prompt](ttl)
+	c.SetMaxEntries(defaultPromptCacheMaxEntries)
+	c.SetMaxStale(defaultPromptCacheMaxStale)
+	return &memoryPromptCache{cache: c}
+}
+
+func (c *memoryPromptCache) Get(_ context.Context, key string) (*CachedPrompt, error) {
+	entry := c.cache.GetEntry(key)
+	if entry == nil {
+		return nil, nil
+	}
+	return &CachedPrompt{Prompt: entry.Value, ExpiresAt: entry.ExpiresAt}, nil
+}
+
+func (c *memoryPromptCache) Set(_ context.Context, key string, entry *CachedPrompt) error {
+	c.cache.SetWithTTL(key, entry.Prompt, time.Until(entry.ExpiresAt))
+	return nil
+}
+
+func (c *memoryPromptCache) Delete(_ context.Context, key string) error {
+	c.cache.Delete(key)
+	return nil
+}
+
+func (c *memoryPromptCache) Clear(_ context.Context) error {
+	c.cache.Clear()
+	return nil
+}
@@ -0,0 +1,28 @@
+package langfuse
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPromptCache_MaxEntriesAndStats(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/api/public/v2/prompts/")
+		writeJSON(t, w, map[string]any{"type": "text", "name": name, "version": 1, "prompt": "Hi"})
+	}).WithPromptCacheMaxEntries(2)
+	ctx := context.Background()
+
+	for _, name := range []string{"a", "b", "a", "c"} {
+		if _, err := l.GetPrompt(ctx, name, nil); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	// b is the least recently used prompt and was evicted for c
+	want := PromptCacheStats{Hits: 1, Misses: 3, Evictions: 1, Size: 2}
+	if stats := l.PromptCacheStats(); stats != want {
+		t.Errorf("expected %+v, got %+v", want, stats)
+	}
+}
@@ -0,0 +1,99 @@
+package langfuse
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// PromptInput contains the values a prompt is compiled with
+type PromptInput struct {
+	Variables    map[string]any
+	Placeholders map[string][]model.ChatMessage
+
+	// Strict fails if a variable or placeholder is missing or unused,
+	// see TextPrompt.CompileStrict.
+	Strict bool
+}
+
+// GenerationFromPrompt compiles the prompt and starts a generation linked to
+// it, with the compiled prompt or messages as input. The "model" of the
+// prompt config is used as the model and its other scalar values as the model
+// parameters, unless they are set on g. The generation name defaults to the prompt
+// name; g may be nil.
+func (l *Langfuse) GenerationFromPrompt(g *model.Generation, prompt *model.Prompt, in *PromptInput, parentID *string) (*model.Generation, error) {
+	if prompt == nil {
+		return nil, fmt.Errorf("prompt is required")
+	}
+	if g == nil {
+		g = &model.Generation{}
+	}
+	if in == nil {
+		in = &PromptInput{}
+	}
+
+	input, err := compilePrompt(prompt, in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile prompt %s: %w", prompt.GetName(), err)
+	}
+
+	g.WithPrompt(prompt)
+	g.Input = input
+	if g.Name == "" {
+		g.Name = prompt.GetName()
+	}
+	if params := modelParameters(prompt.GetConfig()); g.ModelParameters == nil && params != nil {
+		g.ModelParameters = params
+	}
+	if g.Model == "" {
+		// The config may have any shape, so an undecodable config is not an error
+		if config, err := model.DecodeConfig[model.ModelConfig](prompt); err == nil {
+			g.Model = config.Model
+		}
+	}
+
+	return l.Generation(g, parentID)
+}
+
+// compilePrompt returns the compiled text of a text prompt or the compiled
+// messages of a chat prompt
+func compilePrompt(prompt *model.Prompt, in *PromptInput) (any, error) {
+	switch {
+	case prompt.IsText() && in.Strict:
+		return prompt.TextPrompt.CompileStrict(in.Variables)
+	case prompt.IsText():
+		return prompt.TextPrompt.Compile(in.Variables), nil
+	case prompt.IsChat() && in.Strict:
+		return prompt.ChatPrompt.CompileStrict(in.Variables, in.Placeholders)
+	case prompt.IsChat():
+		return prompt.ChatPrompt.Compile(in.Variables, in.Placeholders), nil
+	default:
+		return nil, fmt.Errorf("prompt has no content")
+	}
+}
+
+// modelParameters returns the scalar values of a prompt config except the
+// model, which is set as the generation model. Objects such as tools or a
+// response format are not model parameters.
+func modelParameters(config any) map[string]any {
+	m, ok := config.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	params := map[string]any{}
+	for key, value := range m {
+		if key == "model" {
+			continue
+		}
+		switch value.(type) {
+		case string, bool, float64, float32, int, int64, json.Number:
+			params[key] = value
+		}
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	return params
+}
@@ -0,0 +1,148 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+func newIngestionTestClient(t *testing.T) *Langfuse {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"successes": []any{}, "errors": []any{}})
+	})
+}
+
+func TestGenerationFromPrompt_Chat(t *testing.T) {
+	l := newIngestionTestClient(t)
+
+	prompt := &model.Prompt{ChatPrompt: &model.ChatPrompt{
+		Name:    "support",
+		Version: 7,
+		Config: map[string]any{
+			"model":           "gpt-4o",
+			"temperature":     0.3,
+			"tools":           []any{map[string]any{"type": "function"}},
+			"response_format": map[string]any{"type": "json_object"},
+		},
+		Prompt: []model.ChatMessage{
+			{Role: "system", Content: "You help with {{product}}."},
+			{Type: model.ChatMessageTypePlaceholder, Name: "history"},
+		},
+	}}
+
+	g, err := l.GenerationFromPrompt(&model.Generation{TraceID: "trace-1"}, prompt, &PromptInput{
+		Variables:    map[string]any{"product": "billing"},
+		Placeholders: map[string][]model.ChatMessage{"history": {{Role: "user", Content: "Hi"}}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	l.Flush(context.Background())
+
+	if g.PromptName != "support" || g.PromptVersion != 7 || g.Name != "support" || g.Model != "gpt-4o" {
+		t.Errorf("unexpected generation %+v", g)
+	}
+	if params, ok := g.ModelParameters.(map[string]any); !ok || params["temperature"] != 0.3 || len(params) != 1 {
+		t.Errorf("expected only scalar model parameters, got %v", g.ModelParameters)
+	}
+	if _, ok := g.ModelParameters.(map[string]any)["model"]; ok {
+		t.Error("expected the model not to be a model parameter")
+	}
+
+	messages, ok := g.Input.([]model.ChatMessage)
+	if !ok || len(messages) != 2 || messages[0].Content != "You help with billing." || messages[1].Content != "Hi" {
+		t.Errorf("unexpected input %+v", g.Input)
+	}
+}
+
+func TestGenerationFromPrompt_TextKeepsGenerationFields(t *testing.T) {
+	l := newIngestionTestClient(t)
+
+	prompt := &model.Prompt{TextPrompt: &model.TextPrompt{
+		Name:    "summary",
+		Version: 2,
+		Config:  map[string]any{"model": "gpt-4o-mini"},
+		Prompt:  "Summarize {{text}}",
+	}}
+
+	g, err := l.GenerationFromPrompt(&model.Generation{
+		TraceID:         "trace-1",
+		Name:            "summarize",
+		Model:           "claude",
+		ModelParameters: map[string]any{"max_tokens": 10},
+	}, prompt, &PromptInput{Variables: map[string]any{"text": "this"}}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	l.Flush(context.Background())
+
+	if g.Input != "Summarize this" || g.Name != "summarize" || g.Model != "claude" || g.PromptVersion != 2 {
+		t.Errorf("unexpected generation %+v", g)
+	}
+	if params := g.ModelParameters.(map[string]any); params["max_tokens"] != 10 {
+		t.Errorf("unexpected model parameters %v", params)
+	}
+}
+
+func TestGenerationFromPrompt_Strict(t *testing.T) {
+	l := newIngestionTestClient(t)
+
+	prompt := &model.Prompt{TextPrompt: &model.TextPrompt{Name: "summary", Prompt: "Summarize {{text}}"}}
+
+	_, err := l.GenerationFromPrompt(&model.Generation{TraceID: "trace-1"}, prompt, &PromptInput{Strict: true}, nil)
+
+	var varsErr *model.VariablesError
+	if !errors.As(err, &varsErr) || varsErr.Missing[0] != "text" {
+		t.Errorf("expected missing variable error, got %v", err)
+	}
+}
+
+func TestGeneration_WithPrompt(t *testing.T) {
+	g := (&model.Generation{Name: "gen"}).WithPrompt(&model.Prompt{TextPrompt: &model.TextPrompt{Name: "p", Version: 4}})
+
+	if g.PromptName != "p" || g.PromptVersion != 4 {
+		t.Errorf("unexpected generation %+v", g)
+	}
+}
+
+func TestGeneration_WithFallbackPrompt(t *testing.T) {
+	g := (&model.Generation{}).WithPrompt(&model.Prompt{
+		TextPrompt: &model.TextPrompt{Name: "p", Version: 4},
+		IsFallback: true,
+		Source:     model.PromptSourceFallback,
+	})
+
+	if g.PromptName != "" || g.PromptVersion != 0 {
+		t.Errorf("expected fallback prompt not to be linked, got %+v", g)
+	}
+	if g.Metadata.(map[string]any)["promptSource"] != "fallback" {
+		t.Errorf("unexpected metadata %v", g.Metadata)
+	}
+}
+
+func TestGeneration_WithPromptSource(t *testing.T) {
+	prompt := &model.Prompt{
+		TextPrompt: &model.TextPrompt{Name: "p", Version: 4},
+		Source:     model.PromptSourceFallback,
+		FetchError: errors.New("HTTP 503"),
+	}
+
+	g := (&model.Generation{}).WithPrompt(prompt)
+	metadata, ok := g.Metadata.(map[string]any)
+	if !ok || metadata["promptSource"] != "fallback" || metadata["promptFetchError"] != "HTTP 503" {
+		t.Errorf("unexpected metadata %v", g.Metadata)
+	}
+
+	g = (&model.Generation{Metadata: model.M{"user": "u1"}}).WithPrompt(prompt)
+	if m := g.Metadata.(model.M); m["user"] != "u1" || m["promptSource"] != "fallback" {
+		t.Errorf("unexpected metadata %v", g.Metadata)
+	}
+
+	g = (&model.Generation{Metadata: "opaque"}).WithPrompt(prompt)
+	if g.Metadata != "opaque" {
+		t.Errorf("expected metadata to be kept, got %v", g.Metadata)
+	}
+}
@@ -0,0 +1,228 @@
+// Package promptfile reads and writes Langfuse prompts as YAML or JSON files,
+// so that prompts can be kept in version control or bundled with a binary.
+package promptfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/optible/langfuse-go/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the encoding of a prompt file
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// File is the on-disk representation of a prompt version
+type File struct {
+	Name          string           `json:"name"`
+	Type          model.PromptType `json:"type"`
+	Version       int              `json:"version,omitempty"`
+	Labels        []string         `json:"labels,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+	CommitMessage *string          `json:"commitMessage,omitempty"`
+	Config        any              `json:"config,omitempty"`
+	Prompt        json.RawMessage  `json:"prompt"`
+}
+
+// FormatFromPath returns the format matching the file extension of path
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported prompt file extension: %s", path)
+	}
+}
+
+// Extension returns the file extension for the format, including the dot
+func (f Format) Extension() string {
+	if f == FormatJSON {
+		return ".json"
+	}
+	return ".yaml"
+}
+
+// FromPrompt converts a prompt to its file representation
+func FromPrompt(p *model.Prompt) (*File, error) {
+	var (
+		file   *File
+		prompt any
+	)
+
+	switch {
+	case p.IsText():
+		file = &File{
+			Name:          p.TextPrompt.Name,
+			Type:          model.PromptTypeText,
+			Version:       p.TextPrompt.Version,
+			Labels:        p.TextPrompt.Labels,
+			Tags:          p.TextPrompt.Tags,
+			CommitMessage: p.TextPrompt.CommitMessage,
+			Config:        p.TextPrompt.Config,
+		}
+		prompt = p.TextPrompt.Prompt
+	case p.IsChat():
+		file = &File{
+			Name:          p.ChatPrompt.Name,
+			Type:          model.PromptTypeChat,
+			Version:       p.ChatPrompt.Version,
+			Labels:        p.ChatPrompt.Labels,
+			Tags:          p.ChatPrompt.Tags,
+			CommitMessage: p.ChatPrompt.CommitMessage,
+			Config:        p.ChatPrompt.Config,
+		}
+		prompt = p.ChatPrompt.Prompt
+	default:
+		return nil, fmt.Errorf("prompt is neither a text nor a chat prompt")
+	}
+
+	raw, err := json.Marshal(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode prompt: %w", err)
+	}
+	file.Prompt = raw
+
+	return file, nil
+}
+
+// ToPrompt converts the file representation to a prompt
+func (f *File) ToPrompt() (*model.Prompt, error) {
+	if f.Name == "" {
+		return nil, fmt.Errorf("prompt name is required")
+	}
+
+	switch f.Type {
+	case model.PromptTypeText:
+		var text string
+		if err := json.Unmarshal(f.Prompt, &text); err != nil {
+			return nil, fmt.Errorf("failed to decode text prompt %s: %w", f.Name, err)
+		}
+		return &model.Prompt{TextPrompt: &model.TextPrompt{
+			Name:          f.Name,
+			Version:       f.Version,
+			Config:        f.Config,
+			Labels:        f.Labels,
+			Tags:          f.Tags,
+			Prompt:        text,
+			Type:          model.PromptTypeText,
+			CommitMessage: f.CommitMessage,
+		}}, nil
+
+	case model.PromptTypeChat:
+		var messages []model.ChatMessage
+		if err := json.Unmarshal(f.Prompt, &messages); err != nil {
+			return nil, fmt.Errorf("failed to decode chat prompt %s: %w", f.Name, err)
+		}
+		return &model.Prompt{ChatPrompt: &model.ChatPrompt{
+			Name:          f.Name,
+			Version:       f.Version,
+			Config:        f.Config,
+			Labels:        f.Labels,
+			Tags:          f.Tags,
+			Prompt:        messages,
+			Type:          model.PromptTypeChat,
+			CommitMessage: f.CommitMessage,
+		}}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown prompt type %q for %s", f.Type, f.Name)
+	}
+}
+
+// Marshal encodes a prompt in the given format
+func Marshal(p *model.Prompt, format Format) ([]byte, error) {
+	file, err := FromPrompt(p)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if format == FormatJSON {
+		return append(data, '\n'), nil
+	}
+
+	// YAML files follow the JSON field names, so convert through a generic value
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(generic)
+}
+
+// Unmarshal decodes a prompt encoded in the given format
+func Unmarshal(data []byte, format Format) (*model.Prompt, error) {
+	if format == FormatYAML {
+		var generic any
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+
+		var err error
+		data, err = json.Marshal(generic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert YAML: %w", err)
+		}
+	}
+
+	file := &File{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse prompt file: %w", err)
+	}
+
+	return file.ToPrompt()
+}
+
+// Entry is a prompt read from a file
+type Entry struct {
+	// Path is the slash-separated path of the file within the file system
+	Path   string
+	Prompt *model.Prompt
+}
+
+// ReadFS reads all prompt files in fsys, in lexical order. Files with other
+// extensions are skipped.
+func ReadFS(fsys fs.FS) ([]Entry, error) {
+	var entries []Entry
+
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		format, err := FormatFromPath(path)
+		if err != nil {
+			return nil //nolint:nilerr // skip files that are not prompt files
+		}
+
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+
+		prompt, err := Unmarshal(data, format)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		entries = append(entries, Entry{Path: path, Prompt: prompt})
+		return nil
+	})
+
+	return entries, err
+}
@@ -0,0 +1,123 @@
+package promptfile
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+func TestMarshalUnmarshal_RoundTrip(t *testing.T) {
+	prompts := []*model.Prompt{
+		{TextPrompt: &model.TextPrompt{
+			Name:    "movie-critic",
+			Version: 2,
+			Prompt:  "Review {{movie}}.\nBe {{style}}.",
+			Config:  map[string]any{"temperature": 0.7},
+			Labels:  []string{"production"},
+			Type:    model.PromptTypeText,
+		}},
+		{ChatPrompt: &model.ChatPrompt{
+			Name:    "support/answer",
+			Version: 5,
+			Prompt: []model.ChatMessage{
+				{Role: "system", Content: "You are helpful."},
+				{Role: "user", Content: "{{question}}"},
+			},
+			Tags: []string{"support"},
+			Type: model.PromptTypeChat,
+		}},
+	}
+
+	for _, format := range []Format{FormatYAML, FormatJSON} {
+		for _, prompt := range prompts {
+			data, err := Marshal(prompt, format)
+			if err != nil {
+				t.Fatalf("%s: failed to marshal: %v", format, err)
+			}
+
+			decoded, err := Unmarshal(data, format)
+			if err != nil {
+				t.Fatalf("%s: failed to unmarshal: %v\n%s", format, err, data)
+			}
+
+			if decoded.GetName() != prompt.GetName() || decoded.GetVersion() != prompt.GetVersion() {
+				t.Errorf("%s: unexpected prompt %s v%d", format, decoded.GetName(), decoded.GetVersion())
+			}
+			if decoded.IsText() && decoded.TextPrompt.Prompt != prompt.TextPrompt.Prompt {
+				t.Errorf("%s: unexpected text %q", format, decoded.TextPrompt.Prompt)
+			}
+			if decoded.IsChat() && decoded.ChatPrompt.Prompt[1].Content != "{{question}}" {
+				t.Errorf("%s: unexpected messages %+v", format, decoded.ChatPrompt.Prompt)
+			}
+		}
+	}
+}
+
+func TestMarshal_YAMLUsesJSONFieldNames(t *testing.T) {
+	data, err := Marshal(&model.Prompt{TextPrompt: &model.TextPrompt{
+		Name:   "greeting",
+		Prompt: "Hello",
+		Type:   model.PromptTypeText,
+	}}, FormatYAML)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	for _, field := range []string{"name: greeting", "type: text", "prompt: Hello"} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("expected %q in\n%s", field, data)
+		}
+	}
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	if _, err := Unmarshal([]byte("name: x\ntype: audio\nprompt: hi\n"), FormatYAML); err == nil {
+		t.Error("expected error for unknown prompt type")
+	}
+	if _, err := Unmarshal([]byte(`{"type": "text", "prompt": "hi"}`), FormatJSON); err == nil {
+		t.Error("expected error for missing name")
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	for path, expected := range map[string]Format{"a.yaml": FormatYAML, "b.YML": FormatYAML, "c.json": FormatJSON} {
+		format, err := FormatFromPath(path)
+		if err != nil || format != expected {
+			t.Errorf("%s: expected %s, got %s (%v)", path, expected, format, err)
+		}
+	}
+
+	if _, err := FormatFromPath("README.md"); err == nil {
+		t.Error("expected error for unsupported extension")
+	}
+}
+
+func TestReadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"prompts/greeting.yaml":     {Data: []byte("name: greeting\ntype: text\nprompt: Hi {{name}}\n")},
+		"prompts/support/chat.json": {Data: []byte(`{"name":"support/chat","type":"chat","prompt":[{"role":"system","content":"Be brief."}]}`)},
+		"prompts/README.md":         {Data: []byte("not a prompt")},
+	}
+
+	entries, err := ReadFS(fsys)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Path != "prompts/greeting.yaml" || entries[0].Prompt.GetName() != "greeting" {
+		t.Errorf("unexpected entry %+v", entries[0])
+	}
+	if entries[1].Path != "prompts/support/chat.json" || !entries[1].Prompt.IsChat() {
+		t.Errorf("unexpected entry %+v", entries[1])
+	}
+
+	fsys["prompts/broken.yaml"] = &fstest.MapFile{Data: []byte("type: text\n")}
+	if _, err := ReadFS(fsys); err == nil {
+		t.Error("expected error for invalid prompt file")
+	}
+}
@@ -0,0 +1,321 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// PromptFilter contains the filters for listing prompts.
+// Empty fields are not sent to the API.
+type PromptFilter struct {
+	Name  string
+	Label string
+	Tag   string
+
+	// FromUpdatedAt only includes prompts with versions updated at or after this time.
+	FromUpdatedAt *time.Time
+	// ToUpdatedAt only includes prompts with versions updated before this time.
+	ToUpdatedAt *time.Time
+
+	// Page is the 1-based page to fetch. Defaults to the first page.
+	Page int
+	// Limit is the number of items per page. Defaults to the API's default.
+	Limit int
+}
+
+func (f *PromptFilter) params(page int) url.Values {
+	params := url.Values{}
+	setIntParam(params, "page", page)
+	setIntParam(params, "limit", f.Limit)
+	setParam(params, "name", f.Name)
+	setParam(params, "label", f.Label)
+	setParam(params, "tag", f.Tag)
+	setTimeParam(params, "fromUpdatedAt", f.FromUpdatedAt)
+	setTimeParam(params, "toUpdatedAt", f.ToUpdatedAt)
+	return params
+}
+
+// createPromptRequest is the body of the create prompt endpoint
+type createPromptRequest struct {
+	Type          model.PromptType `json:"type"`
+	Name          string           `json:"name"`
+	Prompt        any              `json:"prompt"`
+	Config        any              `json:"config,omitempty"`
+	Labels        []string         `json:"labels,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+	CommitMessage *string          `json:"commitMessage,omitempty"`
+}
+
+// CreatePrompt creates a new version of a text or chat prompt, creating the
+// prompt if it does not exist yet. The name, prompt, config, labels, tags and
+// commit message are taken from p; the version is assigned by Langfuse.
+func (l *Langfuse) CreatePrompt(ctx context.Context, p *model.Prompt) (*model.Prompt, error) {
+	if p == nil || p.GetName() == "" {
+		return nil, fmt.Errorf("prompt name is required")
+	}
+
+	var req createPromptRequest
+	switch {
+	case p.IsText():
+		req = createPromptRequest{
+			Type:          model.PromptTypeText,
+			Name:          p.TextPrompt.Name,
+			Prompt:        p.TextPrompt.Prompt,
+			Config:        p.TextPrompt.Config,
+			Labels:        p.TextPrompt.Labels,
+			Tags:          p.TextPrompt.Tags,
+			CommitMessage: p.TextPrompt.CommitMessage,
+		}
+	case p.IsChat():
+		req = createPromptRequest{
+			Type:          model.PromptTypeChat,
+			Name:          p.ChatPrompt.Name,
+			Prompt:        p.ChatPrompt.Prompt,
+			Config:        p.ChatPrompt.Config,
+			Labels:        p.ChatPrompt.Labels,
+			Tags:          p.ChatPrompt.Tags,
+			CommitMessage: p.ChatPrompt.CommitMessage,
+		}
+	}
+
+	var body json.RawMessage
+	if err := l.postJSON(ctx, "/api/public/v2/prompts", &req, &body); err != nil {
+		return nil, fmt.Errorf("failed to create prompt: %w", err)
+	}
+
+	created, err := parsePromptResponse(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt response: %w", err)
+	}
+
+	// Langfuse also moves the "latest" label to every new version
+	labels := append([]string{latestPromptLabel}, created.GetLabels()...)
+	l.invalidatePromptLabels(ctx, created.GetName(), labels)
+
+	return created, nil
+}
+
+// UpdatePromptLabels sets the labels of a prompt version, e.g. to promote it
+// to "production". Labels are unique per prompt, so they are removed from any
+// other version that had them.
+func (l *Langfuse) UpdatePromptLabels(ctx context.Context, name string, version int, labels []string) (*model.Prompt, error) {
+	if name == "" {
+		return nil, fmt.Errorf("prompt name is required")
+	}
+
+	if labels == nil {
+		labels = []string{}
+	}
+
+	path := "/api/public/v2/prompts/" + url.PathEscape(name) + "/versions/" + strconv.Itoa(version)
+	req := struct {
+		NewLabels []string `json:"newLabels"`
+	}{NewLabels: labels}
+
+	var body json.RawMessage
+	if err := l.patchJSON(ctx, path, &req, &body); err != nil {
+		return nil, fmt.Errorf("failed to update prompt labels: %w", err)
+	}
+
+	updated, err := parsePromptResponse(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt response: %w", err)
+	}
+
+	// The labels removed from the version are not known, so all cached
+	// versions of the prompt are invalidated
+	l.invalidatePromptVersion(ctx, name, version, labels)
+	l.invalidatePromptName(ctx, name)
+
+	return updated, nil
+}
+
+// ListPrompts fetches a single page of prompt metadata matching the filter.
+// Use IterPrompts to walk all pages.
+func (l *Langfuse) ListPrompts(ctx context.Context, filter *PromptFilter) (*model.PromptMetaList, error) {
+	if filter == nil {
+		filter = &PromptFilter{}
+	}
+
+	return l.listPrompts(ctx, filter, filter.Page)
+}
+
+// IterPrompts returns an iterator over the metadata of all prompts matching
+// the filter, starting at filter.Page.
+func (l *Langfuse) IterPrompts(filter *PromptFilter) *Iterator[model.PromptMeta] {
+	if filter == nil {
+		filter = &PromptFilter{}
+	}
+
+	return newIterator(filter.Page, func(ctx context.Context, page int) ([]model.PromptMeta, model.PaginationMeta, error) {
+		list, err := l.listPrompts(ctx, filter, page)
+		if err != nil {
+			return nil, model.PaginationMeta{}, err
+		}
+		return list.Data, list.Meta, nil
+	})
+}
+
+func (l *Langfuse) listPrompts(ctx context.Context, filter *PromptFilter, page int) (*model.PromptMetaList, error) {
+	list := &model.PromptMetaList{}
+	if err := l.getJSON(ctx, buildPath("/api/public/v2/prompts", filter.params(page)), list); err != nil {
+		return nil, fmt.Errorf("failed to list prompts: %w", err)
+	}
+
+	return list, nil
+}
+
+// InvalidatePrompt removes a prompt version from the prompt cache, so that
+// the next GetPrompt fetches it. Without version or label, the "production"
+// version is removed. Cached composed prompts that embed the prompt are
+// removed as well.
+func (l *Langfuse) InvalidatePrompt(ctx context.Context, ref PromptRef) {
+	l.invalidatePromptKey(ctx, l.buildPromptCacheKey(ref.Name, ref.options()))
+	l.invalidatePromptDependents(ctx, ref.Name)
+}
+
+// invalidatePromptLabels removes the cached prompts fetched by any of the
+// labels, as they may now point to a different version, and the cached
+// composed prompts that embed the prompt
+func (l *Langfuse) invalidatePromptLabels(ctx context.Context, name string, labels []string) {
+	for i := range labels {
+		l.invalidatePromptKey(ctx, l.buildPromptCacheKey(name, &GetPromptOptions{Label: &labels[i]}))
+	}
+	l.invalidatePromptDependents(ctx, name)
+}
+
+// invalidatePromptVersion removes the cached prompts fetched by version or
+// by any of the labels
+func (l *Langfuse) invalidatePromptVersion(ctx context.Context, name string, version int, labels []string) {
+	l.invalidatePromptLabels(ctx, name, labels)
+	if version > 0 {
+		l.invalidatePromptKey(ctx, l.buildPromptCacheKey(name, &GetPromptOptions{Version: &version}))
+	}
+}
+
+// invalidatePromptName removes every cached version of a prompt
+func (l *Langfuse) invalidatePromptName(ctx context.Context, name string) {
+	for _, cacheKey := range l.promptKeys.take(name) {
+		l.invalidatePromptKey(ctx, cacheKey)
+	}
+}
+
+// invalidatePromptKey removes a cached prompt, both resolved and unresolved.
+// Fetches of the prompt already in flight do not cache their result.
+func (l *Langfuse) invalidatePromptKey(ctx context.Context, cacheKey string) {
+	l.promptFetchMu.Lock()
+	for _, key := range []string{cacheKey, cacheKey + ":raw"} {
+		if state, ok := l.promptFetchStates[key]; ok {
+			state.generation++
+		}
+	}
+	l.promptFetchMu.Unlock()
+
+	_ = l.promptCache.Delete(ctx, cacheKey)
+	_ = l.promptCache.Delete(ctx, cacheKey+":raw")
+}
+
+// promptFetchState tracks the fetches of a cache key in flight, and how
+// often the key was invalidated while they were
+type promptFetchState struct {
+	fetches    int
+	generation uint64
+}
+
+// startPromptFetch registers a fetch of a cache key and returns the
+// generation to pass to cachePromptIfCurrent. The caller must call
+// endPromptFetch when done.
+func (l *Langfuse) startPromptFetch(cacheKey string) uint64 {
+	l.promptFetchMu.Lock()
+	defer l.promptFetchMu.Unlock()
+
+	if l.promptFetchStates == nil {
+		l.promptFetchStates = make(map[string]*promptFetchState)
+	}
+	state, ok := l.promptFetchStates[cacheKey]
+	if !ok {
+		state = &promptFetchState{}
+		l.promptFetchStates[cacheKey] = state
+	}
+	state.fetches++
+	return state.generation
+}
+
+// endPromptFetch unregisters a fetch of a cache key, forgetting the key once
+// no fetch is in flight
+func (l *Langfuse) endPromptFetch(cacheKey string) {
+	l.promptFetchMu.Lock()
+	defer l.promptFetchMu.Unlock()
+
+	if state := l.promptFetchStates[cacheKey]; state != nil {
+		state.fetches--
+		if state.fetches == 0 {
+			delete(l.promptFetchStates, cacheKey)
+		}
+	}
+}
+
+// currentPromptFetch reports whether a cache key was not invalidated since
+// the fetch with the generation started
+func (l *Langfuse) currentPromptFetch(cacheKey string, generation uint64) bool {
+	l.promptFetchMu.Lock()
+	defer l.promptFetchMu.Unlock()
+
+	state := l.promptFetchStates[cacheKey]
+	return state != nil && state.generation == generation
+}
+
+// cachePromptIfCurrent caches a fetched prompt unless the cache key was
+// invalidated since the fetch started, as the prompt may then be stale. The
+// cache is not accessed with the lock held, so an invalidation may happen
+// while the prompt is stored, in which case it is removed again.
+func (l *Langfuse) cachePromptIfCurrent(ctx context.Context, cacheKey string, generation uint64, cached *CachedPrompt) {
+	if !l.currentPromptFetch(cacheKey, generation) {
+		return
+	}
+	l.promptKeys.add(cached.Prompt.GetName(), cacheKey)
+	l.recordPromptDependents(cacheKey, cached.Prompt)
+	_ = l.promptCache.Set(ctx, cacheKey, cached)
+	if !l.currentPromptFetch(cacheKey, generation) {
+		_ = l.promptCache.Delete(ctx, cacheKey)
+	}
+}
+
+// promptKeyIndex maps prompt names to cache keys
+type promptKeyIndex struct {
+	mu   sync.Mutex
+	keys map[string]map[string]bool
+}
+
+func (x *promptKeyIndex) add(name, cacheKey string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.keys == nil {
+		x.keys = make(map[string]map[string]bool)
+	}
+	if x.keys[name] == nil {
+		x.keys[name] = make(map[string]bool)
+	}
+	x.keys[name][cacheKey] = true
+}
+
+// take removes and returns the cache keys of a prompt name
+func (x *promptKeyIndex) take(name string) []string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	keys := make([]string, 0, len(x.keys[name]))
+	for cacheKey := range x.keys[name] {
+		keys = append(keys, cacheKey)
+	}
+	delete(x.keys, name)
+	return keys
+}
@@ -0,0 +1,366 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+func TestCreatePrompt_Chat(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/public/v2/prompts" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if body["type"] != "chat" || body["commitMessage"] != "tighten tone" {
+			t.Errorf("unexpected body %v", body)
+		}
+		if _, ok := body["version"]; ok {
+			t.Error("expected version not to be sent")
+		}
+		body["version"] = 4
+		writeJSON(t, w, body)
+	})
+
+	commitMessage := "tighten tone"
+	created, err := l.CreatePrompt(context.Background(), &model.Prompt{
+		ChatPrompt: &model.ChatPrompt{
+			Name:          "support",
+			Prompt:        []model.ChatMessage{{Role: "system", Content: "Be brief."}},
+			Config:        map[string]any{"temperature": 0.2},
+			Labels:        []string{"staging"},
+			CommitMessage: &commitMessage,
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !created.IsChat() || created.GetVersion() != 4 || created.GetLabels()[0] != "staging" {
+		t.Errorf("unexpected prompt %+v", created.ChatPrompt)
+	}
+}
+
+func TestCreatePrompt_WithoutName(t *testing.T) {
+	l := New(context.Background())
+
+	_, err := l.CreatePrompt(context.Background(), &model.Prompt{TextPrompt: &model.TextPrompt{Prompt: "hi"}})
+	if err == nil || err.Error() != "prompt name is required" {
+		t.Errorf("expected 'prompt name is required', got %v", err)
+	}
+}
+
+func TestUpdatePromptLabels(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/public/v2/prompts/support/versions/4" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			NewLabels []string `json:"newLabels"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		writeJSON(t, w, map[string]any{
+			"type":    "text",
+			"name":    "support",
+			"version": 4,
+			"prompt":  "Be brief.",
+			"labels":  body.NewLabels,
+		})
+	})
+
+	production := "production"
+	cacheKey := l.buildPromptCacheKey("support", &GetPromptOptions{Label: &production})
+	_ = l.promptCache.Set(context.Background(), cacheKey, &CachedPrompt{
+		Prompt:    &model.Prompt{TextPrompt: &model.TextPrompt{Name: "support", Version: 3}},
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+
+	updated, err := l.UpdatePromptLabels(context.Background(), "support", 4, []string{"production"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if updated.GetLabels()[0] != "production" {
+		t.Errorf("unexpected labels %v", updated.GetLabels())
+	}
+	if cached, _ := l.promptCache.Get(context.Background(), cacheKey); cached != nil {
+		t.Error("expected cached production prompt to be invalidated")
+	}
+}
+
+func TestUpdatePromptLabels_InvalidatesRemovedLabels(t *testing.T) {
+	var requests atomic.Int32
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			requests.Add(1)
+		}
+		writeJSON(t, w, map[string]any{"type": "text", "name": "support", "version": 4, "prompt": "Be brief.", "labels": []string{"staging"}})
+	})
+	ctx := context.Background()
+	label := "staging"
+	staging := &GetPromptOptions{Label: &label}
+
+	if _, err := l.GetPrompt(ctx, "support", staging); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// "staging" is moved off version 4, which is only known from the cache
+	if _, err := l.UpdatePromptLabels(ctx, "support", 4, []string{"production"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, _ = l.GetPrompt(ctx, "support", staging)
+	if requests.Load() != 2 {
+		t.Errorf("expected the staging prompt to be refetched, got %d requests", requests.Load())
+	}
+}
+
+func TestListPrompts(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("tag") != "support" {
+			t.Errorf("unexpected tag filter %q", r.URL.Query().Get("tag"))
+		}
+		writeJSON(t, w, map[string]any{
+			"data": []map[string]any{
+				{"name": "support", "versions": []int{1, 2}, "labels": []string{"production"}, "tags": []string{"support"}},
+			},
+			"meta": map[string]any{"page": 1, "limit": 50, "totalItems": 1, "totalPages": 1},
+		})
+	})
+
+	list, err := l.ListPrompts(context.Background(), &PromptFilter{Tag: "support"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(list.Data) != 1 || len(list.Data[0].Versions) != 2 {
+		t.Errorf("unexpected list %+v", list.Data)
+	}
+}
+
+func TestGetPrompt_ChatPlaceholders(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{
+			"type":    "chat",
+			"name":    "assistant",
+			"version": 1,
+			"prompt": []map[string]any{
+				{"type": "chatmessage", "role": "system", "content": "You are {{persona}}."},
+				{"type": "placeholder", "name": "history"},
+			},
+		})
+	})
+
+	prompt, err := l.GetPrompt(context.Background(), "assistant", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	messages := prompt.ChatPrompt.Compile(map[string]any{"persona": "a pirate"}, map[string][]model.ChatMessage{
+		"history": {{Role: "user", Content: "Ahoy"}},
+	})
+	if len(messages) != 2 || messages[0].Content != "You are a pirate." || messages[1].Content != "Ahoy" {
+		t.Errorf("unexpected messages %+v", messages)
+	}
+}
+
+func TestGetPrompt_StaleWhileRevalidate(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		if n > 1 {
+			<-release
+		}
+		writeJSON(t, w, map[string]any{"type": "text", "name": "greeting", "version": n, "prompt": "Hi"})
+	})
+	ttl := time.Millisecond
+
+	prompt, err := l.GetPrompt(context.Background(), "greeting", &GetPromptOptions{CacheTTL: &ttl})
+	if err != nil || prompt.GetVersion() != 1 {
+		t.Fatalf("unexpected prompt %+v, error %v", prompt, err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// While the refresh is blocked, readers get the stale version immediately
+	for i := 0; i < 5; i++ {
+		prompt, err = l.GetPrompt(context.Background(), "greeting", nil)
+		if err != nil || prompt.GetVersion() != 1 {
+			t.Fatalf("expected stale version 1, got %+v, error %v", prompt, err)
+		}
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		prompt, _ = l.GetPrompt(context.Background(), "greeting", nil)
+		if prompt.GetVersion() == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if prompt.GetVersion() != 2 {
+		t.Errorf("expected refreshed version 2, got %d", prompt.GetVersion())
+	}
+	if requests.Load() != 2 {
+		t.Errorf("expected a single background refresh, got %d requests", requests.Load())
+	}
+}
+
+func TestGetPrompt_ConcurrentMissesShareRequest(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		writeJSON(t, w, map[string]any{"type": "text", "name": "greeting", "version": 1, "prompt": "Hi"})
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := l.GetPrompt(context.Background(), "greeting", nil); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		}()
+	}
+
+	// Give the goroutines time to join the in-flight request
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %d", requests.Load())
+	}
+}
+
+func TestGetPrompt_CanceledCallerDoesNotFailWaiters(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		writeJSON(t, w, map[string]any{"type": "text", "name": "greeting", "version": 1, "prompt": "Hi"})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := l.GetPrompt(ctx, "greeting", nil)
+		leaderErr <- err
+	}()
+	<-started
+
+	waiter := make(chan *model.Prompt, 1)
+	go func() {
+		prompt, err := l.GetPrompt(context.Background(), "greeting", nil)
+		if err != nil {
+			t.Errorf("expected waiter to get the prompt, got %v", err)
+		}
+		waiter <- prompt
+	}()
+
+	// The first caller gives up while the waiter joins the shared fetch
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceled caller to stop waiting, got %v", err)
+	}
+
+	close(release)
+	if prompt := <-waiter; prompt == nil || prompt.GetVersion() != 1 {
+		t.Errorf("unexpected prompt %+v", prompt)
+	}
+}
+
+func TestGetPrompt_Source(t *testing.T) {
+	var offline atomic.Bool
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if offline.Load() {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(t, w, map[string]any{"type": "text", "name": "greeting", "version": 1, "prompt": "Hi"})
+	})
+	ctx := context.Background()
+	ttl := time.Millisecond
+
+	prompt, err := l.GetPrompt(ctx, "greeting", &GetPromptOptions{CacheTTL: &ttl})
+	if err != nil || prompt.Source != model.PromptSourceFresh || prompt.FetchError != nil {
+		t.Fatalf("expected fresh prompt, got %+v, %v", prompt, err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	offline.Store(true)
+
+	// The first stale read triggers a refresh that fails
+	prompt, _ = l.GetPrompt(ctx, "greeting", nil)
+	if prompt.Source != model.PromptSourceStale {
+		t.Errorf("expected stale prompt, got %q", prompt.Source)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for prompt.FetchError == nil && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		prompt, _ = l.GetPrompt(ctx, "greeting", nil)
+	}
+	if prompt.Source != model.PromptSourceStale || prompt.FetchError == nil {
+		t.Errorf("expected stale prompt with the refresh error, got %q, %v", prompt.Source, prompt.FetchError)
+	}
+
+	fallback := &model.Prompt{TextPrompt: &model.TextPrompt{Name: "other", Prompt: "Hello"}}
+	prompt, err = l.GetPrompt(ctx, "other", &GetPromptOptions{FallbackPrompt: fallback})
+	if err != nil || prompt.Source != model.PromptSourceFallback || !prompt.IsFallback || prompt.FetchError == nil {
+		t.Errorf("expected fallback with fetch error, got %+v, %v", prompt, err)
+	}
+
+	offline.Store(false)
+	if _, err := l.GetPrompt(ctx, "cached", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	prompt, _ = l.GetPrompt(ctx, "cached", nil)
+	if prompt.Source != model.PromptSourceCached {
+		t.Errorf("expected cached prompt, got %q", prompt.Source)
+	}
+}
+
+func TestCreatePrompt_InvalidatesLatest(t *testing.T) {
+	var version atomic.Int32
+	version.Store(1)
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			version.Add(1)
+		}
+		writeJSON(t, w, map[string]any{"type": "text", "name": "greeting", "version": version.Load(), "prompt": "Hi"})
+	})
+	ctx := context.Background()
+	latest := "latest"
+
+	if _, err := l.GetPrompt(ctx, "greeting", &GetPromptOptions{Label: &latest}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := l.CreatePrompt(ctx, &model.Prompt{TextPrompt: &model.TextPrompt{Name: "greeting", Prompt: "Hi"}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	prompt, _ := l.GetPrompt(ctx, "greeting", &GetPromptOptions{Label: &latest})
+	if prompt.GetVersion() != 2 {
+		t.Errorf("expected latest to be refetched, got version %d", prompt.GetVersion())
+	}
+}
@@ -0,0 +1,111 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// getJSON performs a GET request against the Langfuse API and decodes the
+// JSON response body into out.
+func (l *Langfuse) getJSON(ctx context.Context, path string, out any) error {
+	body, err := l.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// postJSON performs a POST request against the Langfuse API with in encoded
+// as JSON and decodes the JSON response body into out, if out is not nil.
+func (l *Langfuse) postJSON(ctx context.Context, path string, in, out any) error {
+	return l.sendJSON(ctx, http.MethodPost, path, in, out)
+}
+
+// patchJSON performs a PATCH request against the Langfuse API with in encoded
+// as JSON and decodes the JSON response body into out, if out is not nil.
+func (l *Langfuse) patchJSON(ctx context.Context, path string, in, out any) error {
+	return l.sendJSON(ctx, http.MethodPatch, path, in, out)
+}
+
+func (l *Langfuse) sendJSON(ctx context.Context, method, path string, in, out any) error {
+	reqBody, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	body, err := l.do(ctx, method, path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// deleteResource performs a DELETE request against the Langfuse API
+func (l *Langfuse) deleteResource(ctx context.Context, path string) error {
+	_, err := l.do(ctx, http.MethodDelete, path, nil)
+	return err
+}
+
+// do performs a request against the Langfuse API and returns the response
+// body, or an *APIError if the response has an error status
+func (l *Langfuse) do(ctx context.Context, method, path string, reqBody []byte) ([]byte, error) {
+	res, err := l.client.DoRequest(ctx, method, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, newAPIError(method, path, res.StatusCode, res.Header, res.Body)
+	}
+
+	return res.Body, nil
+}
+
+// buildPath appends the encoded query parameters to path, if any
+func buildPath(path string, params url.Values) string {
+	if len(params) == 0 {
+		return path
+	}
+
+	return path + "?" + params.Encode()
+}
+
+// setParam sets a query parameter if value is not empty
+func setParam(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
+
+// setIntParam sets a query parameter if value is positive
+func setIntParam(params url.Values, key string, value int) {
+	if value > 0 {
+		params.Set(key, strconv.Itoa(value))
+	}
+}
+
+// setTimeParam sets a query parameter to the ISO 8601 representation of t if t is not nil
+func setTimeParam(params url.Values, key string, t *time.Time) {
+	if t != nil {
+		params.Set(key, t.UTC().Format(time.RFC3339Nano))
+	}
+}
@@ -0,0 +1,140 @@
+package langfuse
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// ScoreFilter contains the filters for listing scores.
+// Empty fields are not sent to the API.
+type ScoreFilter struct {
+	Name          string
+	TraceID       string
+	ObservationID string
+	SessionID     string
+	UserID        string
+	ConfigID      string
+	Source        model.ScoreSource
+	DataType      model.ScoreDataType
+	Environment   []string
+
+	// MinValue only includes scores with a value greater than or equal to it.
+	MinValue *float64
+	// MaxValue only includes scores with a value less than or equal to it.
+	// The API accepts a single value comparison, so when both MinValue and
+	// MaxValue are set MaxValue is applied client-side and a page may
+	// contain fewer than Limit items.
+	MaxValue *float64
+
+	// FromTimestamp only includes scores created at or after this time.
+	FromTimestamp *time.Time
+	// ToTimestamp only includes scores created before this time.
+	ToTimestamp *time.Time
+
+	// Page is the 1-based page to fetch. Defaults to the first page.
+	Page int
+	// Limit is the number of items per page. Defaults to the API's default.
+	Limit int
+}
+
+func (f *ScoreFilter) params(page int) url.Values {
+	params := url.Values{}
+	setIntParam(params, "page", page)
+	setIntParam(params, "limit", f.Limit)
+	setParam(params, "name", f.Name)
+	setParam(params, "traceId", f.TraceID)
+	setParam(params, "observationId", f.ObservationID)
+	setParam(params, "sessionId", f.SessionID)
+	setParam(params, "userId", f.UserID)
+	setParam(params, "configId", f.ConfigID)
+	setParam(params, "source", string(f.Source))
+	setParam(params, "dataType", string(f.DataType))
+	for _, env := range f.Environment {
+		params.Add("environment", env)
+	}
+	setTimeParam(params, "fromTimestamp", f.FromTimestamp)
+	setTimeParam(params, "toTimestamp", f.ToTimestamp)
+
+	if f.MinValue != nil {
+		params.Set("operator", ">=")
+		params.Set("value", strconv.FormatFloat(*f.MinValue, 'f', -1, 64))
+	} else if f.MaxValue != nil {
+		params.Set("operator", "<=")
+		params.Set("value", strconv.FormatFloat(*f.MaxValue, 'f', -1, 64))
+	}
+
+	return params
+}
+
+// filterMaxValue drops the scores above MaxValue when the API could not apply it
+func (f *ScoreFilter) filterMaxValue(scores []model.Score) []model.Score {
+	if f.MinValue == nil || f.MaxValue == nil {
+		return scores
+	}
+
+	filtered := scores[:0]
+	for _, score := range scores {
+		if score.Value <= *f.MaxValue {
+			filtered = append(filtered, score)
+		}
+	}
+	return filtered
+}
+
+// GetScore fetches a score by its ID.
+func (l *Langfuse) GetScore(ctx context.Context, scoreID string) (*model.Score, error) {
+	if scoreID == "" {
+		return nil, fmt.Errorf("score ID is required")
+	}
+
+	score := &model.Score{}
+	err := l.getJSON(ctx, "/api/public/v2/scores/"+url.PathEscape(scoreID), score)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get score: %w", err)
+	}
+
+	return score, nil
+}
+
+// ListScores fetches a single page of scores matching the filter.
+// Use IterScores to walk all pages.
+func (l *Langfuse) ListScores(ctx context.Context, filter *ScoreFilter) (*model.ScoreList, error) {
+	if filter == nil {
+		filter = &ScoreFilter{}
+	}
+
+	return l.listScores(ctx, filter, filter.Page)
+}
+
+// IterScores returns an iterator over all scores matching the filter,
+// starting at filter.Page.
+func (l *Langfuse) IterScores(filter *ScoreFilter) *Iterator[model.Score] {
+	if filter == nil {
+		filter = &ScoreFilter{}
+	}
+
+	return newIterator(filter.Page, func(ctx context.Context, page int) ([]model.Score, model.PaginationMeta, error) {
+		list, err := l.listScores(ctx, filter, page)
+		if err != nil {
+			return nil, model.PaginationMeta{}, err
+		}
+		return list.Data, list.Meta, nil
+	})
+}
+
+func (l *Langfuse) listScores(ctx context.Context, filter *ScoreFilter, page int) (*model.ScoreList, error) {
+	list := &model.ScoreList{}
+	err := l.getJSON(ctx, buildPath("/api/public/v2/scores", filter.params(page)), list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scores: %w", err)
+	}
+
+	list.Data = filter.filterMaxValue(list.Data)
+
+	return list, nil
+}
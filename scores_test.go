@@ -0,0 +1,122 @@
+package langfuse
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+func TestGetScore(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/v2/scores/score-1" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		writeJSON(t, w, map[string]any{
+			"id":       "score-1",
+			"traceId":  "trace-1",
+			"name":     "quality",
+			"value":    0.8,
+			"source":   "API",
+			"dataType": "NUMERIC",
+		})
+	})
+
+	score, err := l.GetScore(context.Background(), "score-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if score.Value != 0.8 || score.Source != model.ScoreSourceAPI || score.DataType != model.ScoreDataTypeNumeric {
+		t.Errorf("unexpected score: %+v", score)
+	}
+}
+
+func TestListScores_Filter(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("name") != "quality" || q.Get("source") != "EVAL" || q.Get("dataType") != "NUMERIC" {
+			t.Errorf("unexpected filters: %v", q)
+		}
+		if q.Get("operator") != ">=" || q.Get("value") != "0.5" {
+			t.Errorf("unexpected value filter %q %q", q.Get("operator"), q.Get("value"))
+		}
+		writeJSON(t, w, map[string]any{
+			"data": []map[string]any{
+				{"id": "score-1", "value": 0.6},
+				{"id": "score-2", "value": 0.95},
+				{"id": "score-3", "value": 0.7},
+			},
+			"meta": map[string]any{"page": 1, "limit": 50, "totalItems": 3, "totalPages": 1},
+		})
+	})
+
+	minValue, maxValue := 0.5, 0.9
+	list, err := l.ListScores(context.Background(), &ScoreFilter{
+		Name:     "quality",
+		Source:   model.ScoreSourceEval,
+		DataType: model.ScoreDataTypeNumeric,
+		MinValue: &minValue,
+		MaxValue: &maxValue,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(list.Data) != 2 {
+		t.Fatalf("expected 2 scores within range, got %d", len(list.Data))
+	}
+	if list.Data[0].ID != "score-1" || list.Data[1].ID != "score-3" {
+		t.Errorf("unexpected scores: %+v", list.Data)
+	}
+}
+
+func TestListScores_MaxValueOnly(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("operator") != "<=" || q.Get("value") != "1" {
+			t.Errorf("unexpected value filter %q %q", q.Get("operator"), q.Get("value"))
+		}
+		writeJSON(t, w, map[string]any{"data": []any{}, "meta": map[string]any{}})
+	})
+
+	maxValue := 1.0
+	if _, err := l.ListScores(context.Background(), &ScoreFilter{MaxValue: &maxValue}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestGetScore_WithEmptyID(t *testing.T) {
+	l := New(context.Background())
+
+	_, err := l.GetScore(context.Background(), "")
+	if err == nil || err.Error() != "score ID is required" {
+		t.Errorf("expected 'score ID is required', got %v", err)
+	}
+}
+
+func TestIterScores_SkipsEmptyFilteredPage(t *testing.T) {
+	pages := map[string][]map[string]any{
+		"1": {{"id": "score-1", "value": 0.6}},
+		"2": {{"id": "score-2", "value": 0.95}},
+		"3": {{"id": "score-3", "value": 0.7}},
+	}
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{
+			"data": pages[r.URL.Query().Get("page")],
+			"meta": map[string]any{"page": 1, "limit": 1, "totalItems": 3, "totalPages": 3},
+		})
+	})
+
+	// Page 2 is emptied by the client-side MaxValue filter
+	minValue, maxValue := 0.5, 0.9
+	scores, err := l.IterScores(&ScoreFilter{MinValue: &minValue, MaxValue: &maxValue}).All(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(scores) != 2 || scores[0].ID != "score-1" || scores[1].ID != "score-3" {
+		t.Errorf("unexpected scores: %+v", scores)
+	}
+}
@@ -0,0 +1,89 @@
+package langfuse
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/optible/langfuse-go/model"
+)
+
+// SessionFilter contains the filters for listing sessions.
+// Empty fields are not sent to the API.
+type SessionFilter struct {
+	Environment []string
+
+	// FromTimestamp only includes sessions created at or after this time.
+	FromTimestamp *time.Time
+	// ToTimestamp only includes sessions created before this time.
+	ToTimestamp *time.Time
+
+	// Page is the 1-based page to fetch. Defaults to the first page.
+	Page int
+	// Limit is the number of items per page. Defaults to the API's default.
+	Limit int
+}
+
+func (f *SessionFilter) params(page int) url.Values {
+	params := url.Values{}
+	setIntParam(params, "page", page)
+	setIntParam(params, "limit", f.Limit)
+	for _, env := range f.Environment {
+		params.Add("environment", env)
+	}
+	setTimeParam(params, "fromTimestamp", f.FromTimestamp)
+	setTimeParam(params, "toTimestamp", f.ToTimestamp)
+	return params
+}
+
+// GetSession fetches a session by its ID, including all of its traces.
+func (l *Langfuse) GetSession(ctx context.Context, sessionID string) (*model.SessionWithTraces, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("session ID is required")
+	}
+
+	session := &model.SessionWithTraces{}
+	err := l.getJSON(ctx, "/api/public/sessions/"+url.PathEscape(sessionID), session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return session, nil
+}
+
+// ListSessions fetches a single page of sessions matching the filter.
+// Use IterSessions to walk all pages.
+func (l *Langfuse) ListSessions(ctx context.Context, filter *SessionFilter) (*model.SessionList, error) {
+	if filter == nil {
+		filter = &SessionFilter{}
+	}
+
+	return l.listSessions(ctx, filter, filter.Page)
+}
+
+// IterSessions returns an iterator over all sessions matching the filter,
+// starting at filter.Page.
+func (l *Langfuse) IterSessions(filter *SessionFilter) *Iterator[model.Session] {
+	if filter == nil {
+		filter = &SessionFilter{}
+	}
+
+	return newIterator(filter.Page, func(ctx context.Context, page int) ([]model.Session, model.PaginationMeta, error) {
+		list, err := l.listSessions(ctx, filter, page)
+		if err != nil {
+			return nil, model.PaginationMeta{}, err
+		}
+		return list.Data, list.Meta, nil
+	})
+}
+
+func (l *Langfuse) listSessions(ctx context.Context, filter *SessionFilter, page int) (*model.SessionList, error) {
+	list := &model.SessionList{}
+	err := l.getJSON(ctx, buildPath("/api/public/sessions", filter.params(page)), list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,82 @@
+package langfuse
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestGetSession(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/sessions/session-1" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		writeJSON(t, w, map[string]any{
+			"id":        "session-1",
+			"createdAt": "2024-01-01T00:00:00Z",
+			"traces": []map[string]any{
+				{"id": "trace-1", "sessionId": "session-1", "input": "hi"},
+				{"id": "trace-2", "sessionId": "session-1", "input": "bye"},
+			},
+		})
+	})
+
+	session, err := l.GetSession(context.Background(), "session-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if session.ID != "session-1" {
+		t.Errorf("expected session ID 'session-1', got %q", session.ID)
+	}
+	if session.CreatedAt == nil {
+		t.Error("expected createdAt to be parsed")
+	}
+	if len(session.Traces) != 2 || session.Traces[1].ID != "trace-2" {
+		t.Errorf("unexpected traces: %+v", session.Traces)
+	}
+}
+
+func TestGetSession_WithEmptyID(t *testing.T) {
+	l := New(context.Background())
+
+	_, err := l.GetSession(context.Background(), "")
+	if err == nil || err.Error() != "session ID is required" {
+		t.Errorf("expected 'session ID is required', got %v", err)
+	}
+}
+
+func TestListSessions_TimeRange(t *testing.T) {
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("fromTimestamp") != "2024-01-01T00:00:00Z" {
+			t.Errorf("unexpected fromTimestamp %q", q.Get("fromTimestamp"))
+		}
+		if q.Get("toTimestamp") != "2024-02-01T00:00:00Z" {
+			t.Errorf("unexpected toTimestamp %q", q.Get("toTimestamp"))
+		}
+		if q.Get("page") != "2" || q.Get("limit") != "10" {
+			t.Errorf("unexpected pagination %q/%q", q.Get("page"), q.Get("limit"))
+		}
+		writeJSON(t, w, map[string]any{
+			"data": []map[string]any{{"id": "session-1"}},
+			"meta": map[string]any{"page": 2, "limit": 10, "totalItems": 11, "totalPages": 2},
+		})
+	})
+
+	from := mustParseTime(t, "2024-01-01T00:00:00Z")
+	to := mustParseTime(t, "2024-02-01T00:00:00Z")
+	list, err := l.ListSessions(context.Background(), &SessionFilter{
+		FromTimestamp: &from,
+		ToTimestamp:   &to,
+		Page:          2,
+		Limit:         10,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(list.Data) != 1 || list.Meta.Page != 2 {
+		t.Errorf("unexpected list: %+v", list)
+	}
+}
@@ -0,0 +1,118 @@
+package langfuse
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	webhookSignatureHeader    = "X-Langfuse-Signature"
+	webhookSignatureTolerance = 5 * time.Minute
+	webhookMaxBodySize        = 5 << 20
+
+	// promptVersionEvent is the type of the events about prompt versions
+	promptVersionEvent = "prompt-version"
+)
+
+// promptWebhookEvent is the payload of a Langfuse prompt webhook
+type promptWebhookEvent struct {
+	Type   string `json:"type"`
+	Action string `json:"action"`
+	Prompt struct {
+		Name    string   `json:"name"`
+		Version int      `json:"version"`
+		Labels  []string `json:"labels"`
+	} `json:"prompt"`
+}
+
+// PromptWebhookHandler returns a handler for Langfuse prompt webhooks that
+// invalidates the cached prompts of changed prompt versions, so that for
+// example a version promoted to "production" is used right away instead of
+// after the cache TTL. Requests are verified with the webhook secret.
+//
+// The version and the labels it has after the change are invalidated. Events
+// of other types are acknowledged and ignored. An error is returned if
+// secret is empty, as anyone could then sign requests.
+func (l *Langfuse) PromptWebhookHandler(secret string) (http.Handler, error) {
+	if secret == "" {
+		return nil, errors.New("webhook secret is required")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhookMaxBodySize))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		if err := verifyWebhookSignature(secret, r.Header.Get(webhookSignatureHeader), body, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		var event promptWebhookEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, "failed to parse event", http.StatusBadRequest)
+			return
+		}
+
+		if event.Type == promptVersionEvent && event.Prompt.Name != "" {
+			l.invalidatePromptVersion(r.Context(), event.Prompt.Name, event.Prompt.Version, event.Prompt.Labels)
+		}
+		w.WriteHeader(http.StatusOK)
+	}), nil
+}
+
+// verifyWebhookSignature checks a signature header of the form
+// "t=<unix timestamp>,v1=<hex HMAC-SHA256 of timestamp.body>"
+func verifyWebhookSignature(secret, header string, body []byte, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return errors.New("missing webhook signature")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid webhook timestamp: %w", err)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > webhookSignatureTolerance || age < -webhookSignatureTolerance {
+		return errors.New("webhook timestamp outside of tolerance")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	for _, signature := range signatures {
+		if decoded, err := hex.DecodeString(signature); err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return errors.New("invalid webhook signature")
+}
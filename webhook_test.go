@@ -0,0 +1,174 @@
+package langfuse
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newVersionedPromptClient(t *testing.T) (*Langfuse, *atomic.Int32) {
+	var version atomic.Int32
+	version.Store(1)
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"type": "text", "name": "greeting", "version": version.Load(), "prompt": "Hi"})
+	})
+	return l, &version
+}
+
+func signWebhook(secret string, timestamp time.Time, body string) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "." + body))
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestPromptWebhookHandler(t *testing.T) {
+	l, version := newVersionedPromptClient(t)
+	ctx := context.Background()
+
+	if _, err := l.GetPrompt(ctx, "greeting", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Version 2 is promoted to production
+	version.Store(2)
+	body := `{"type":"prompt-version","action":"updated","prompt":{"name":"greeting","version":2,"labels":["production"]}}`
+
+	tests := []struct {
+		name        string
+		signature   string
+		want        int
+		wantVersion int
+	}{
+		{"missing signature", "", http.StatusUnauthorized, 1},
+		{"wrong secret", signWebhook("other", time.Now(), body), http.StatusUnauthorized, 1},
+		{"expired timestamp", signWebhook("secret", time.Now().Add(-time.Hour), body), http.StatusUnauthorized, 1},
+		{"valid signature", signWebhook("secret", time.Now(), body), http.StatusOK, 2},
+	}
+
+	handler, err := l.PromptWebhookHandler("secret")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/langfuse", strings.NewReader(body))
+			req.Header.Set("X-Langfuse-Signature", tt.signature)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, rec.Code)
+			}
+
+			prompt, _ := l.GetPrompt(ctx, "greeting", nil)
+			if prompt.GetVersion() != tt.wantVersion {
+				t.Errorf("expected version %d, got %d", tt.wantVersion, prompt.GetVersion())
+			}
+		})
+	}
+}
+
+func TestInvalidatePrompt(t *testing.T) {
+	l, version := newVersionedPromptClient(t)
+	ctx := context.Background()
+	raw := &GetPromptOptions{Resolution: ResolveNone}
+
+	_, _ = l.GetPrompt(ctx, "greeting", nil)
+	_, _ = l.GetPrompt(ctx, "greeting", raw)
+	version.Store(2)
+
+	l.InvalidatePrompt(ctx, PromptRef{Name: "greeting", Label: "production"})
+
+	for _, opts := range []*GetPromptOptions{nil, raw} {
+		if prompt, _ := l.GetPrompt(ctx, "greeting", opts); prompt.GetVersion() != 2 {
+			t.Errorf("expected refetched version 2, got %d", prompt.GetVersion())
+		}
+	}
+}
+
+func TestPromptWebhookHandler_EmptySecret(t *testing.T) {
+	l, _ := newVersionedPromptClient(t)
+
+	if handler, err := l.PromptWebhookHandler(""); err == nil || handler != nil {
+		t.Errorf("expected an error for an empty secret, got %v", err)
+	}
+}
+
+func TestPromptWebhookHandler_IgnoresOtherEvents(t *testing.T) {
+	l, version := newVersionedPromptClient(t)
+	ctx := context.Background()
+
+	if _, err := l.GetPrompt(ctx, "greeting", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	version.Store(2)
+
+	handler, err := l.PromptWebhookHandler("secret")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	body := `{"type":"trace","action":"created","prompt":{"name":"greeting","version":2,"labels":["production"]}}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/langfuse", strings.NewReader(body))
+	req.Header.Set("X-Langfuse-Signature", signWebhook("secret", time.Now(), body))
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if prompt, _ := l.GetPrompt(ctx, "greeting", nil); prompt.GetVersion() != 1 {
+		t.Errorf("expected cached version 1, got %d", prompt.GetVersion())
+	}
+}
+
+func TestInvalidatePrompt_FetchInFlight(t *testing.T) {
+	var version atomic.Int32
+	version.Store(1)
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	l := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		v := version.Load()
+		if v == 1 {
+			started <- struct{}{}
+			<-release
+		}
+		writeJSON(t, w, map[string]any{"type": "text", "name": "greeting", "version": v, "prompt": "Hi"})
+	})
+	ctx := context.Background()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = l.GetPrompt(ctx, "greeting", nil)
+	}()
+
+	// Version 2 is promoted while version 1 is being fetched
+	<-started
+	version.Store(2)
+	l.InvalidatePrompt(ctx, PromptRef{Name: "greeting"})
+	close(release)
+	<-done
+
+	if prompt, _ := l.GetPrompt(ctx, "greeting", nil); prompt.GetVersion() != 2 {
+		t.Errorf("expected refetched version 2, got %d", prompt.GetVersion())
+	}
+
+	l.promptFetchMu.Lock()
+	defer l.promptFetchMu.Unlock()
+	if len(l.promptFetchStates) != 0 {
+		t.Errorf("expected no tracked fetches, got %d", len(l.promptFetchStates))
+	}
+}